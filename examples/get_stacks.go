@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
 )
@@ -60,10 +61,28 @@ func listAllStacks(client *enbuild.Client) {
 	printStacks(allStacks)
 }
 
+func listFailedStacks(client *enbuild.Client) {
+	opts := &enbuild.StackListOptions{
+		ListOptions:  enbuild.ListOptions{Page: 1, Limit: 10},
+		Status:       "failed",
+		CreatedAfter: time.Now().AddDate(0, 0, -7),
+		Sort:         enbuild.SortDesc("createdOn"),
+	}
+	fmt.Printf("Listing failed Stacks created in the last 7 days\n")
+	failedStacks, err := client.Stacks.ListStacksWithOptions(context.Background(), opts)
+	if err != nil {
+		log.Fatalf("Error listing Stacks: %v", err)
+	}
+
+	fmt.Printf("Found: %d failed stacks\n", len(failedStacks))
+	printStacks(failedStacks)
+}
+
 func main() {
 	client, err := createClient()
 	if err != nil {
 		log.Fatalf("Error creating client: %v", err)
 	}
 	listAllStacks(client)
+	listFailedStacks(client)
 }
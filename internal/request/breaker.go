@@ -0,0 +1,258 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned when a request is rejected because the circuit for its host is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of the circuit kept for a single host
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects every request until the open timeout has passed
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of probe requests through to test recovery
+	CircuitHalfOpen
+)
+
+// String returns the lower case name of the state
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+const (
+	defaultConsecutiveFailures = 5
+	defaultMinRequests         = 10
+	defaultFailureInterval     = time.Minute
+	defaultOpenTimeout         = 30 * time.Second
+	defaultHalfOpenRequests    = 1
+)
+
+// CircuitBreakerSettings configures a CircuitBreaker. Zero values fall back to the defaults noted on each field.
+type CircuitBreakerSettings struct {
+	// ConsecutiveFailures opens the circuit after this many failures in a row (default 5)
+	ConsecutiveFailures int
+	// FailureRatio opens the circuit when this share of requests in the current interval failed.
+	// Zero disables the ratio check.
+	FailureRatio float64
+	// MinRequests is how many requests the interval needs before FailureRatio applies (default 10)
+	MinRequests int
+	// Interval is the window over which requests are counted while the circuit is closed (default 1m)
+	Interval time.Duration
+	// OpenTimeout is how long the circuit stays open before probing the host again (default 30s)
+	OpenTimeout time.Duration
+	// HalfOpenMaxRequests is how many probe requests may run while half-open (default 1)
+	HalfOpenMaxRequests int
+	// OnStateChange, when set, is called after the circuit of a host changes state
+	OnStateChange func(host string, from, to CircuitState)
+}
+
+// CircuitBreaker fails requests fast while a host keeps failing. State is kept per host.
+type CircuitBreaker struct {
+	settings CircuitBreakerSettings
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+// circuit holds the counters of a single host
+type circuit struct {
+	state       CircuitState
+	requests    int
+	failures    int
+	consecutive int
+	windowStart time.Time
+	openedAt    time.Time
+	probes      int
+	// generation changes with every state transition. Outcomes of requests admitted
+	// in an earlier generation are ignored, so they cannot decide the current state.
+	generation uint64
+}
+
+// NewCircuitBreaker creates a CircuitBreaker, applying defaults to unset settings
+func NewCircuitBreaker(settings CircuitBreakerSettings) *CircuitBreaker {
+	if settings.ConsecutiveFailures <= 0 {
+		settings.ConsecutiveFailures = defaultConsecutiveFailures
+	}
+	if settings.MinRequests <= 0 {
+		settings.MinRequests = defaultMinRequests
+	}
+	if settings.Interval <= 0 {
+		settings.Interval = defaultFailureInterval
+	}
+	if settings.OpenTimeout <= 0 {
+		settings.OpenTimeout = defaultOpenTimeout
+	}
+	if settings.HalfOpenMaxRequests <= 0 {
+		settings.HalfOpenMaxRequests = defaultHalfOpenRequests
+	}
+
+	return &CircuitBreaker{
+		settings: settings,
+		circuits: make(map[string]*circuit),
+	}
+}
+
+// State returns the current state of the circuit for host
+func (b *CircuitBreaker) State(host string) CircuitState {
+	b.mu.Lock()
+	cb := b.circuit(host)
+	from := cb.state
+	b.advance(cb, time.Now())
+	to := cb.state
+	b.mu.Unlock()
+
+	b.notify(host, from, to)
+	return to
+}
+
+// Allow reports whether a request to host may be sent. On success it returns a function
+// that must be called with the outcome of the request once it has completed.
+func (b *CircuitBreaker) Allow(host string) (func(failed bool), error) {
+	b.mu.Lock()
+	cb := b.circuit(host)
+	from := cb.state
+	b.advance(cb, time.Now())
+	to := cb.state
+
+	var err error
+	switch {
+	case cb.state == CircuitOpen:
+		err = fmt.Errorf("%w: %s", ErrCircuitOpen, host)
+	case cb.state == CircuitHalfOpen && cb.probes >= b.settings.HalfOpenMaxRequests:
+		err = fmt.Errorf("%w: %s is being probed", ErrCircuitOpen, host)
+	case cb.state == CircuitHalfOpen:
+		cb.probes++
+	}
+	generation := cb.generation
+	b.mu.Unlock()
+
+	b.notify(host, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(failed bool) { b.record(host, generation, failed) }, nil
+}
+
+// record updates the counters of host with the outcome of a request admitted in generation
+func (b *CircuitBreaker) record(host string, generation uint64, failed bool) {
+	b.mu.Lock()
+	cb := b.circuit(host)
+	if cb.generation != generation {
+		b.mu.Unlock()
+		return
+	}
+	from := cb.state
+	now := time.Now()
+
+	switch cb.state {
+	case CircuitHalfOpen:
+		cb.probes--
+		if failed {
+			b.open(cb, now)
+		} else {
+			b.close(cb, now)
+		}
+	case CircuitClosed:
+		if now.Sub(cb.windowStart) > b.settings.Interval {
+			b.resetWindow(cb, now)
+		}
+		cb.requests++
+		if failed {
+			cb.failures++
+			cb.consecutive++
+		} else {
+			cb.consecutive = 0
+		}
+		if b.shouldOpen(cb) {
+			b.open(cb, now)
+		}
+	}
+	to := cb.state
+	b.mu.Unlock()
+
+	b.notify(host, from, to)
+}
+
+// shouldOpen reports whether the failure counters of a closed circuit have crossed a threshold
+func (b *CircuitBreaker) shouldOpen(cb *circuit) bool {
+	if cb.consecutive >= b.settings.ConsecutiveFailures {
+		return true
+	}
+	if b.settings.FailureRatio > 0 && cb.requests >= b.settings.MinRequests {
+		return float64(cb.failures)/float64(cb.requests) >= b.settings.FailureRatio
+	}
+	return false
+}
+
+// advance moves an open circuit to half-open once the open timeout has passed
+func (b *CircuitBreaker) advance(cb *circuit, now time.Time) {
+	if cb.state == CircuitOpen && now.Sub(cb.openedAt) >= b.settings.OpenTimeout {
+		cb.state = CircuitHalfOpen
+		cb.probes = 0
+		cb.generation++
+	}
+}
+
+func (b *CircuitBreaker) open(cb *circuit, now time.Time) {
+	cb.state = CircuitOpen
+	cb.openedAt = now
+	cb.generation++
+}
+
+func (b *CircuitBreaker) close(cb *circuit, now time.Time) {
+	cb.state = CircuitClosed
+	cb.generation++
+	b.resetWindow(cb, now)
+}
+
+// resetWindow starts a new failure counting window
+func (b *CircuitBreaker) resetWindow(cb *circuit, now time.Time) {
+	cb.requests = 0
+	cb.failures = 0
+	cb.consecutive = 0
+	cb.windowStart = now
+}
+
+// circuit returns the circuit of host, creating it on first use. The caller must hold b.mu.
+func (b *CircuitBreaker) circuit(host string) *circuit {
+	cb, ok := b.circuits[host]
+	if !ok {
+		cb = &circuit{windowStart: time.Now()}
+		b.circuits[host] = cb
+	}
+	return cb
+}
+
+// notify calls the state change callback if the state changed
+func (b *CircuitBreaker) notify(host string, from, to CircuitState) {
+	if from != to && b.settings.OnStateChange != nil {
+		b.settings.OnStateChange(host, from, to)
+	}
+}
+
+// isFailure reports whether the outcome of a request counts against the circuit of its host.
+// Transport errors and 5xx responses count, client errors and caller cancellations do not.
+func isFailure(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return req.Context().Err() == nil
+	}
+	return resp.StatusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,164 @@
+package request_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/internal/request"
+)
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	type transition struct {
+		from, to request.CircuitState
+	}
+	var transitions []transition
+
+	breaker := request.NewCircuitBreaker(request.CircuitBreakerSettings{
+		ConsecutiveFailures: 2,
+		OpenTimeout:         20 * time.Millisecond,
+		OnStateChange: func(host string, from, to request.CircuitState) {
+			if host != "api.example.com" {
+				t.Errorf("Unexpected host %q", host)
+			}
+			transitions = append(transitions, transition{from, to})
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		done, err := breaker.Allow("api.example.com")
+		if err != nil {
+			t.Fatalf("Allow returned error while closed: %v", err)
+		}
+		done(true)
+	}
+
+	if _, err := breaker.Allow("api.example.com"); !errors.Is(err, request.ErrCircuitOpen) {
+		t.Fatalf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if state := breaker.State("other.example.com"); state != request.CircuitClosed {
+		t.Errorf("Expected other hosts to stay closed, got %s", state)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	probe, err := breaker.Allow("api.example.com")
+	if err != nil {
+		t.Fatalf("Expected a half-open probe to be allowed, got %v", err)
+	}
+	if _, err := breaker.Allow("api.example.com"); !errors.Is(err, request.ErrCircuitOpen) {
+		t.Errorf("Expected a second probe to be rejected, got %v", err)
+	}
+	probe(false)
+
+	want := []transition{
+		{request.CircuitClosed, request.CircuitOpen},
+		{request.CircuitOpen, request.CircuitHalfOpen},
+		{request.CircuitHalfOpen, request.CircuitClosed},
+	}
+	if len(transitions) != len(want) {
+		t.Fatalf("Expected transitions %v, got %v", want, transitions)
+	}
+	for i := range want {
+		if transitions[i] != want[i] {
+			t.Errorf("Transition %d: expected %v, got %v", i, want[i], transitions[i])
+		}
+	}
+}
+
+func TestCircuitBreakerIgnoresStaleOutcomes(t *testing.T) {
+	breaker := request.NewCircuitBreaker(request.CircuitBreakerSettings{
+		ConsecutiveFailures: 1,
+		OpenTimeout:         20 * time.Millisecond,
+	})
+
+	// A slow request admitted while closed, finishing after the circuit went half-open
+	slow, err := breaker.Allow("api.example.com")
+	if err != nil {
+		t.Fatalf("Allow returned error while closed: %v", err)
+	}
+	failing, err := breaker.Allow("api.example.com")
+	if err != nil {
+		t.Fatalf("Allow returned error while closed: %v", err)
+	}
+	failing(true)
+
+	time.Sleep(30 * time.Millisecond)
+
+	probe, err := breaker.Allow("api.example.com")
+	if err != nil {
+		t.Fatalf("Expected a half-open probe to be allowed, got %v", err)
+	}
+	slow(false)
+	if state := breaker.State("api.example.com"); state != request.CircuitHalfOpen {
+		t.Fatalf("Expected the stale outcome to leave the circuit half-open, got %s", state)
+	}
+	if _, err := breaker.Allow("api.example.com"); !errors.Is(err, request.ErrCircuitOpen) {
+		t.Errorf("Expected the stale outcome not to free a probe slot, got %v", err)
+	}
+
+	probe(true)
+	if state := breaker.State("api.example.com"); state != request.CircuitOpen {
+		t.Errorf("Expected the failed probe to reopen the circuit, got %s", state)
+	}
+}
+
+func TestCircuitBreakerFailureRatio(t *testing.T) {
+	breaker := request.NewCircuitBreaker(request.CircuitBreakerSettings{
+		ConsecutiveFailures: 100,
+		FailureRatio:        0.5,
+		MinRequests:         4,
+	})
+
+	for _, failed := range []bool{true, false, true, false} {
+		done, err := breaker.Allow("api.example.com")
+		if err != nil {
+			t.Fatalf("Allow returned error while closed: %v", err)
+		}
+		done(failed)
+	}
+
+	if state := breaker.State("api.example.com"); state != request.CircuitOpen {
+		t.Errorf("Expected circuit to open at 50%% failures, got %s", state)
+	}
+}
+
+func TestDoFailsFastWhenCircuitOpen(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := &request.Client{
+		BaseURL:        serverURL,
+		HTTPClient:     server.Client(),
+		CircuitBreaker: request.NewCircuitBreaker(request.CircuitBreakerSettings{ConsecutiveFailures: 1, OpenTimeout: time.Hour}),
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		req, err := client.NewRequest(ctx, http.MethodGet, "/down", nil)
+		if err != nil {
+			t.Fatalf("NewRequest failed: %v", err)
+		}
+		_, err = client.Do(ctx, req, nil)
+		if err == nil {
+			t.Fatalf("Expected an error on call %d", i)
+		}
+		if i > 0 && !errors.Is(err, request.ErrCircuitOpen) {
+			t.Errorf("Expected ErrCircuitOpen on call %d, got %v", i, err)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected the server to be called once, got %d", calls)
+	}
+}
@@ -0,0 +1,86 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrNotFound is matched by errors.Is for 404 responses and for lookups that found nothing
+var ErrNotFound = errors.New("not found")
+
+// maxErrorBody caps how much of an error response body is read
+const maxErrorBody = 1 << 20
+
+// APIError is returned for responses outside the 2xx range. It carries the error body ENBUILD sends:
+//
+//	{"statusCode": 400, "message": "Error", "error": "Error message"}
+//
+// where message may also be a list of validation messages.
+type APIError struct {
+	Response   *http.Response
+	StatusCode int
+	// Message is the message of the error body, validation messages joined with "; "
+	Message string
+	// Messages holds the individual validation messages
+	Messages []string
+	// ErrorText is the error field of the error body
+	ErrorText string
+	// Body is the raw error body
+	Body []byte
+}
+
+// Error returns the status line followed by the server message, if any
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("API error: %s", e.Response.Status)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	} else if e.ErrorText != "" {
+		msg += ": " + e.ErrorText
+	}
+	return msg
+}
+
+// IsValidation reports whether the server rejected the request body
+func (e *APIError) IsValidation() bool {
+	return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+}
+
+// Is lets errors.Is match ErrNotFound on 404 responses
+func (e *APIError) Is(target error) bool {
+	return target == ErrNotFound && e.StatusCode == http.StatusNotFound
+}
+
+// newAPIError reads the error body of resp
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{Response: resp, StatusCode: resp.StatusCode}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	if err != nil || len(body) == 0 {
+		return apiErr
+	}
+	apiErr.Body = body
+
+	var errorBody struct {
+		Message json.RawMessage `json:"message"`
+		Error   string          `json:"error"`
+	}
+	if err := json.Unmarshal(body, &errorBody); err != nil {
+		return apiErr
+	}
+	apiErr.ErrorText = errorBody.Error
+
+	var message string
+	var messages []string
+	if err := json.Unmarshal(errorBody.Message, &message); err == nil {
+		apiErr.Message = message
+	} else if err := json.Unmarshal(errorBody.Message, &messages); err == nil {
+		apiErr.Messages = messages
+		apiErr.Message = strings.Join(messages, "; ")
+	}
+
+	return apiErr
+}
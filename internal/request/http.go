@@ -9,8 +9,12 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 )
 
+// defaultRetryBackoff is how long to wait after a 429 response that carries no Retry-After header
+const defaultRetryBackoff = time.Second
+
 // TokenProvider is a function that returns an authentication token
 type TokenProvider func(ctx context.Context) string
 
@@ -22,6 +26,15 @@ type Client struct {
 	AuthToken     string
 	Debug         bool
 	TokenProvider TokenProvider
+
+	// RateLimiter, when set, is waited on before every request and paused when the server answers 429
+	RateLimiter *RateLimiter
+	// Semaphore, when set, caps the number of requests in flight
+	Semaphore *Semaphore
+	// CircuitBreaker, when set, rejects requests with ErrCircuitOpen while their host keeps failing
+	CircuitBreaker *CircuitBreaker
+	// MaxRetries is how many times a request answered with 429 Too Many Requests is retried
+	MaxRetries int
 }
 
 // NewRequest creates a new HTTP request
@@ -67,26 +80,66 @@ func (c *Client) NewRequest(ctx context.Context, method, path string, body inter
 	return req, nil
 }
 
-// Do sends an HTTP request and returns an HTTP response
-func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
-	if c.Debug {
-		fmt.Printf("Making request to: %s %s\n", req.Method, req.URL.String())
-
-		// Print request headers in debug mode
-		fmt.Println("Request headers:")
-		for key, values := range req.Header {
-			// Mask the Authorization header for security
-			if strings.ToLower(key) == "authorization" {
-				fmt.Printf("  %s: Bearer ****\n", key)
-			} else {
-				fmt.Printf("  %s: %s\n", key, strings.Join(values, ", "))
-			}
+// Do sends an HTTP request and returns the response along with its metadata
+func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*Response, error) {
+	if c.Semaphore != nil {
+		if err := c.Semaphore.Acquire(ctx); err != nil {
+			return nil, err
 		}
+		defer c.Semaphore.Release()
 	}
 
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, err
+	var resp *http.Response
+	for attempt := 0; ; attempt++ {
+		if c.RateLimiter != nil {
+			if err := c.RateLimiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		if c.Debug {
+			c.debugRequest(req)
+		}
+
+		var done func(failed bool)
+		if c.CircuitBreaker != nil {
+			var err error
+			if done, err = c.CircuitBreaker.Allow(req.URL.Host); err != nil {
+				return nil, err
+			}
+		}
+
+		var err error
+		resp, err = c.HTTPClient.Do(req)
+		if done != nil {
+			done(isFailure(req, resp, err))
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusTooManyRequests {
+			break
+		}
+
+		// Back off for as long as the server asked, sharing the pause with every other caller of this client
+		wait := parseRetryAfter(resp.Header, defaultRetryBackoff<<attempt)
+		if c.RateLimiter != nil {
+			c.RateLimiter.Pause(wait)
+		}
+		if attempt >= c.MaxRetries || !rewindBody(req) {
+			break
+		}
+		resp.Body.Close()
+
+		if c.Debug {
+			fmt.Printf("Rate limited by %s, retrying in %s\n", req.URL.Host, wait)
+		}
+		if c.RateLimiter == nil {
+			if err := sleep(ctx, wait); err != nil {
+				return nil, err
+			}
+		}
 	}
 	defer resp.Body.Close()
 
@@ -100,15 +153,48 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*htt
 		resp.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
 	}
 
+	response := newResponse(resp)
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return resp, fmt.Errorf("API error: %s", resp.Status)
+		return response, newAPIError(resp)
 	}
 
 	if v != nil {
 		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
-			return resp, err
+			return response, err
 		}
 	}
 
-	return resp, nil
+	return response, nil
+}
+
+// debugRequest prints the request line and headers, masking the Authorization header
+func (c *Client) debugRequest(req *http.Request) {
+	fmt.Printf("Making request to: %s %s\n", req.Method, req.URL.String())
+
+	fmt.Println("Request headers:")
+	for key, values := range req.Header {
+		// Mask the Authorization header for security
+		if strings.ToLower(key) == "authorization" {
+			fmt.Printf("  %s: Bearer ****\n", key)
+		} else {
+			fmt.Printf("  %s: %s\n", key, strings.Join(values, ", "))
+		}
+	}
+}
+
+// rewindBody resets the request body so the request can be sent again.
+// It reports false when the body cannot be replayed.
+func rewindBody(req *http.Request) bool {
+	if req.Body == nil || req.Body == http.NoBody {
+		return true
+	}
+	if req.GetBody == nil {
+		return false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return false
+	}
+	req.Body = body
+	return true
 }
@@ -0,0 +1,95 @@
+package request
+
+import (
+	"context"
+	"iter"
+)
+
+// PageFetcher fetches a single page of a list endpoint
+type PageFetcher[T any] func(ctx context.Context, page int) ([]T, *Response, error)
+
+// Pager walks all the pages of a list endpoint, following Response.NextPage
+type Pager[T any] struct {
+	// Fetch retrieves the given page
+	Fetch PageFetcher[T]
+	// FirstPage is the page the walk starts at
+	FirstPage int
+	// Prefetch fetches the next page in the background while the current one is consumed
+	Prefetch bool
+}
+
+// pageResult is the outcome of fetching a single page
+type pageResult[T any] struct {
+	items []T
+	resp  *Response
+	err   error
+}
+
+// All returns an iterator over the items of every page. Iteration stops at the first error,
+// which is yielded with a zero item, when the caller breaks out of the loop, or when ctx is done.
+func (p *Pager[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		page := p.FirstPage
+		pending := p.fetch(ctx, page)
+		for {
+			result := <-pending
+			if result.err == nil {
+				result.err = ctx.Err()
+			}
+			if result.err != nil {
+				var zero T
+				yield(zero, result.err)
+				return
+			}
+
+			next := 0
+			if result.resp != nil {
+				next = result.resp.NextPage
+			}
+			// A next page that does not move forward would loop forever
+			hasNext := next > page
+			if hasNext && p.Prefetch {
+				pending = p.fetch(ctx, next)
+			}
+
+			for _, item := range result.items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if !hasNext {
+				return
+			}
+			if !p.Prefetch {
+				pending = p.fetch(ctx, next)
+			}
+			page = next
+		}
+	}
+}
+
+// Collect returns the items of every page
+func (p *Pager[T]) Collect(ctx context.Context) ([]T, error) {
+	var items []T
+	for item, err := range p.All(ctx) {
+		if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// fetch retrieves a page in the background. The channel is buffered so an abandoned fetch never blocks.
+func (p *Pager[T]) fetch(ctx context.Context, page int) <-chan pageResult[T] {
+	ch := make(chan pageResult[T], 1)
+	go func() {
+		items, resp, err := p.Fetch(ctx, page)
+		ch <- pageResult[T]{items: items, resp: resp, err: err}
+	}()
+	return ch
+}
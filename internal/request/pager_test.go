@@ -0,0 +1,114 @@
+package request_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/internal/request"
+)
+
+// fakePages serves pages of three items, with pages numbered from 1 to lastPage
+type fakePages struct {
+	mu       sync.Mutex
+	lastPage int
+	fetched  []int
+}
+
+func (f *fakePages) fetch(ctx context.Context, page int) ([]int, *request.Response, error) {
+	f.mu.Lock()
+	f.fetched = append(f.fetched, page)
+	f.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	items := []int{page*10 + 1, page*10 + 2, page*10 + 3}
+	resp := &request.Response{Response: &http.Response{StatusCode: http.StatusOK}, Page: page}
+	if page < f.lastPage {
+		resp.NextPage = page + 1
+	}
+	return items, resp, nil
+}
+
+func TestPagerCollect(t *testing.T) {
+	for _, prefetch := range []bool{false, true} {
+		pages := &fakePages{lastPage: 3}
+		pager := &request.Pager[int]{Fetch: pages.fetch, FirstPage: 1, Prefetch: prefetch}
+
+		items, err := pager.Collect(context.Background())
+		if err != nil {
+			t.Fatalf("Collect returned error: %v", err)
+		}
+		if len(items) != 9 || items[0] != 11 || items[8] != 33 {
+			t.Errorf("Prefetch=%v: unexpected items %v", prefetch, items)
+		}
+	}
+}
+
+func TestPagerStopsOnBreak(t *testing.T) {
+	pages := &fakePages{lastPage: 100}
+	pager := &request.Pager[int]{Fetch: pages.fetch, FirstPage: 1}
+
+	var items []int
+	for item, err := range pager.All(context.Background()) {
+		if err != nil {
+			t.Fatalf("All yielded error: %v", err)
+		}
+		items = append(items, item)
+		if len(items) == 4 {
+			break
+		}
+	}
+
+	if len(pages.fetched) != 2 {
+		t.Errorf("Expected 2 pages to be fetched, got %v", pages.fetched)
+	}
+}
+
+func TestPagerYieldsErrors(t *testing.T) {
+	fetchErr := errors.New("boom")
+	pager := &request.Pager[int]{
+		FirstPage: 1,
+		Fetch: func(ctx context.Context, page int) ([]int, *request.Response, error) {
+			if page == 2 {
+				return nil, nil, fetchErr
+			}
+			return []int{1}, &request.Response{NextPage: page + 1}, nil
+		},
+	}
+
+	items, err := pager.Collect(context.Background())
+	if !errors.Is(err, fetchErr) {
+		t.Errorf("Expected fetch error, got %v", err)
+	}
+	if len(items) != 1 {
+		t.Errorf("Expected the items fetched before the error, got %v", items)
+	}
+}
+
+func TestPagerHonorsCancellation(t *testing.T) {
+	pages := &fakePages{lastPage: 100}
+	pager := &request.Pager[int]{Fetch: pages.fetch, FirstPage: 1, Prefetch: true}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var lastErr error
+	count := 0
+	for _, err := range pager.All(ctx) {
+		if err != nil {
+			lastErr = err
+			break
+		}
+		if count++; count == 3 {
+			cancel()
+		}
+	}
+
+	if !errors.Is(lastErr, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", lastErr)
+	}
+}
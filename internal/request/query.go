@@ -0,0 +1,206 @@
+package request
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// EncodeQuery encodes an options struct into a URL query string.
+//
+// Fields are read from `url` struct tags in declaration order, e.g. `url:"search,omitempty"`.
+// A tag of "-" skips the field and untagged fields use their Go name. Supported tag options are:
+//
+//	omitempty  skip zero values
+//	comma      join slice elements with commas instead of repeating the key
+//	unix       encode time.Time as seconds since the epoch
+//	unixmilli  encode time.Time as milliseconds since the epoch
+//
+// Strings, ints, uints, floats, bools, time.Time (RFC 3339 by default), encoding.TextMarshaler
+// values, pointers, slices and embedded structs are supported. A nil options value encodes to "".
+func EncodeQuery(opts interface{}) (string, error) {
+	v := reflect.ValueOf(opts)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return "", nil
+	}
+	if v.Kind() != reflect.Struct {
+		return "", fmt.Errorf("query options must be a struct, got %s", v.Kind())
+	}
+
+	var parts []string
+	if err := encodeStruct(v, &parts); err != nil {
+		return "", err
+	}
+	return strings.Join(parts, "&"), nil
+}
+
+// AddQuery appends the query encoded from opts to path
+func AddQuery(path string, opts interface{}) (string, error) {
+	query, err := EncodeQuery(opts)
+	if err != nil {
+		return "", err
+	}
+	if query == "" {
+		return path, nil
+	}
+	if strings.Contains(path, "?") {
+		return path + "&" + query, nil
+	}
+	return path + "?" + query, nil
+}
+
+// encodeStruct appends the encoded fields of v to parts
+func encodeStruct(v reflect.Value, parts *[]string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		tag := field.Tag.Get("url")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		opts := tagOptions(options)
+
+		fv := v.Field(i)
+		if field.Anonymous && name == "" {
+			for fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					break
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct && fv.Type() != timeType {
+				if err := encodeStruct(fv, parts); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		if opts.has("omitempty") && isEmptyValue(fv) {
+			continue
+		}
+
+		values, err := encodeValue(fv, opts)
+		if err != nil {
+			return fmt.Errorf("encoding query field %s: %w", field.Name, err)
+		}
+		if opts.has("comma") && len(values) > 0 {
+			values = []string{strings.Join(values, ",")}
+		}
+		for _, value := range values {
+			*parts = append(*parts, url.QueryEscape(name)+"="+url.QueryEscape(value))
+		}
+	}
+	return nil
+}
+
+// encodeValue returns the query values for a single field, more than one for slices
+func encodeValue(v reflect.Value, opts tagOptions) ([]string, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() == timeType {
+		return []string{encodeTime(v.Interface().(time.Time), opts)}, nil
+	}
+	if v.Kind() != reflect.Ptr && v.CanAddr() && reflect.PtrTo(v.Type()).Implements(textMarshalerType) {
+		v = v.Addr()
+	}
+	if v.Type().Implements(textMarshalerType) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return []string{string(text)}, nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return []string{v.String()}, nil
+	case reflect.Bool:
+		return []string{strconv.FormatBool(v.Bool())}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return []string{strconv.FormatInt(v.Int(), 10)}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return []string{strconv.FormatUint(v.Uint(), 10)}, nil
+	case reflect.Float32, reflect.Float64:
+		return []string{strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits())}, nil
+	case reflect.Slice, reflect.Array:
+		var values []string
+		for i := 0; i < v.Len(); i++ {
+			elem, err := encodeValue(v.Index(i), opts)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, elem...)
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("unsupported kind %s", v.Kind())
+	}
+}
+
+// encodeTime formats t according to the time related tag options
+func encodeTime(t time.Time, opts tagOptions) string {
+	switch {
+	case opts.has("unix"):
+		return strconv.FormatInt(t.Unix(), 10)
+	case opts.has("unixmilli"):
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	default:
+		return t.Format(time.RFC3339)
+	}
+}
+
+// isEmptyValue reports whether v is the zero value for omitempty purposes
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return v.Interface().(time.Time).IsZero()
+		}
+	}
+	return v.IsZero()
+}
+
+// tagOptions is the comma separated list of options following the name in a url tag
+type tagOptions string
+
+func (o tagOptions) has(option string) bool {
+	for _, s := range strings.Split(string(o), ",") {
+		if s == option {
+			return true
+		}
+	}
+	return false
+}
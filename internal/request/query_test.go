@@ -0,0 +1,95 @@
+package request_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/internal/request"
+)
+
+type querySort string
+
+func (s querySort) MarshalText() ([]byte, error) {
+	return []byte("-" + string(s)), nil
+}
+
+type queryPage struct {
+	Page  int `url:"page,omitempty"`
+	Limit int `url:"limit,omitempty"`
+}
+
+type queryOptions struct {
+	queryPage
+	Search  string    `url:"search"`
+	Status  []string  `url:"status,omitempty"`
+	Tags    []string  `url:"tags,omitempty,comma"`
+	Active  *bool     `url:"active,omitempty"`
+	After   time.Time `url:"after,omitempty"`
+	Before  time.Time `url:"before,omitempty,unix"`
+	Sort    querySort `url:"sort,omitempty"`
+	Ratio   float64   `url:"ratio,omitempty"`
+	Skipped string    `url:"-"`
+}
+
+func TestEncodeQuery(t *testing.T) {
+	active := false
+	after := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		opts     interface{}
+		expected string
+	}{
+		{
+			name:     "NilOptions",
+			opts:     (*queryOptions)(nil),
+			expected: "",
+		},
+		{
+			name:     "ZeroValues",
+			opts:     &queryOptions{},
+			expected: "search=",
+		},
+		{
+			name:     "EmbeddedFieldsInOrder",
+			opts:     queryOptions{queryPage: queryPage{Page: 2, Limit: 10}, Search: "a&b=c"},
+			expected: "page=2&limit=10&search=a%26b%3Dc",
+		},
+		{
+			name:     "SlicesRepeatOrJoin",
+			opts:     &queryOptions{Status: []string{"failed", "success"}, Tags: []string{"x", "y"}},
+			expected: "search=&status=failed&status=success&tags=x%2Cy",
+		},
+		{
+			name:     "PointersTimesAndMarshalers",
+			opts:     &queryOptions{Active: &active, After: after, Before: after, Sort: "createdOn", Ratio: 0.5, Skipped: "x"},
+			expected: "search=&active=false&after=2024-05-01T12%3A00%3A00Z&before=1714564800&sort=-createdOn&ratio=0.5",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			query, err := request.EncodeQuery(tc.opts)
+			if err != nil {
+				t.Fatalf("EncodeQuery returned error: %v", err)
+			}
+			if query != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, query)
+			}
+		})
+	}
+}
+
+func TestAddQuery(t *testing.T) {
+	path, err := request.AddQuery("stacks?x=1", &queryPage{Page: 1})
+	if err != nil {
+		t.Fatalf("AddQuery returned error: %v", err)
+	}
+	if path != "stacks?x=1&page=1" {
+		t.Errorf("Expected stacks?x=1&page=1, got %s", path)
+	}
+
+	if _, err := request.AddQuery("stacks", 42); err == nil {
+		t.Errorf("Expected an error for non-struct options")
+	}
+}
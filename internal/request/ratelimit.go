@@ -0,0 +1,141 @@
+package request
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket limiter shared by all requests made through a Client.
+// Besides the steady rate, it holds every caller back while the server asks for a pause via 429 Retry-After.
+type RateLimiter struct {
+	mu          sync.Mutex
+	rate        float64 // tokens added per second, zero means unlimited
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+// NewRateLimiter creates a RateLimiter allowing rps requests per second with bursts of up to burst requests.
+// A zero rps does not limit the rate but still honors server-requested pauses.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or the context is done
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		delay := l.reserve()
+		if delay <= 0 {
+			return nil
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// Pause holds back all callers for d, unless an earlier pause already lasts longer
+func (l *RateLimiter) Pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// reserve takes a token if one is available, otherwise it returns how long to wait for the next one
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+
+	if l.rate <= 0 {
+		return 0
+	}
+
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// Semaphore caps the number of requests in flight at the same time
+type Semaphore struct {
+	slots chan struct{}
+}
+
+// NewSemaphore creates a Semaphore allowing up to n concurrent holders
+func NewSemaphore(n int) *Semaphore {
+	if n < 1 {
+		n = 1
+	}
+	return &Semaphore{slots: make(chan struct{}, n)}
+}
+
+// Acquire blocks until a slot is free or the context is done
+func (s *Semaphore) Acquire(ctx context.Context) error {
+	select {
+	case s.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release frees a slot taken by Acquire
+func (s *Semaphore) Release() {
+	<-s.slots
+}
+
+// parseRetryAfter reads the Retry-After header, which is either a number of seconds or an HTTP date
+func parseRetryAfter(h http.Header, fallback time.Duration) time.Duration {
+	value := h.Get("Retry-After")
+	if value == "" {
+		return fallback
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+		return 0
+	}
+	return fallback
+}
+
+// sleep waits for d or until the context is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
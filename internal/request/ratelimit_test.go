@@ -0,0 +1,113 @@
+package request_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/internal/request"
+)
+
+func TestRateLimiterWaitHonorsContext(t *testing.T) {
+	limiter := request.NewRateLimiter(0.001, 1)
+
+	// The first token comes from the burst
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("first Wait returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestDoRetriesTooManyRequests(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := &request.Client{
+		BaseURL:     serverURL,
+		HTTPClient:  server.Client(),
+		RateLimiter: request.NewRateLimiter(0, 1),
+		MaxRetries:  1,
+	}
+
+	ctx := context.Background()
+	req, err := client.NewRequest(ctx, http.MethodPost, "/retry", map[string]string{"field": "data"})
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+
+	var responseData map[string]string
+	if _, err := client.Do(ctx, req, &responseData); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls)
+	}
+	if responseData["status"] != "ok" {
+		t.Errorf("Response data not decoded correctly. Got %v", responseData)
+	}
+}
+
+func TestDoMaxConcurrentRequests(t *testing.T) {
+	var inFlight, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := &request.Client{
+		BaseURL:    serverURL,
+		HTTPClient: server.Client(),
+		Semaphore:  request.NewSemaphore(2),
+	}
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := client.NewRequest(ctx, http.MethodGet, "/slow", nil)
+			if err != nil {
+				t.Errorf("NewRequest failed: %v", err)
+				return
+			}
+			if _, err := client.Do(ctx, req, nil); err != nil {
+				t.Errorf("Do returned error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("Expected at most 2 requests in flight, got %d", peak)
+	}
+}
@@ -0,0 +1,165 @@
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Response wraps an http.Response with the metadata ENBUILD sends alongside the data
+type Response struct {
+	*http.Response
+
+	// Pagination values, zero when the server did not report them.
+	// NextPage is zero on the last page.
+	Total    int
+	Page     int
+	Limit    int
+	NextPage int
+
+	// Rate holds the rate limit headers of the response
+	Rate Rate
+
+	// CorrelationID identifies the request in the ENBUILD backend logs
+	CorrelationID string
+}
+
+// Rate represents the rate limit headers returned by the server
+type Rate struct {
+	Limit      int
+	Remaining  int
+	Reset      time.Time
+	RetryAfter time.Duration
+}
+
+// Pagination holds the pagination fields list endpoints may send next to data
+type Pagination struct {
+	Total int `json:"total,omitempty"`
+	Page  int `json:"page,omitempty"`
+	Limit int `json:"limit,omitempty"`
+	// Next is a page number, a URL or a boolean depending on the endpoint
+	Next json.RawMessage `json:"next,omitempty"`
+}
+
+// newResponse creates a Response, reading the metadata carried in headers and the request query
+func newResponse(r *http.Response) *Response {
+	response := &Response{Response: r}
+
+	if r.Request != nil {
+		query := r.Request.URL.Query()
+		response.Page, _ = strconv.Atoi(query.Get("page"))
+		response.Limit, _ = strconv.Atoi(query.Get("limit"))
+	}
+	response.Total, _ = strconv.Atoi(r.Header.Get("X-Total-Count"))
+	response.Rate = parseRate(r.Header)
+	response.CorrelationID = firstHeader(r.Header, "X-Correlation-Id", "X-Request-Id")
+
+	return response
+}
+
+// SetPagination fills the pagination values from the body of a list response
+// that returned count items, and works out the next page
+func (r *Response) SetPagination(p Pagination, count int) {
+	if p.Total > 0 {
+		r.Total = p.Total
+	}
+	if p.Page > 0 {
+		r.Page = p.Page
+	}
+	if p.Limit > 0 {
+		r.Limit = p.Limit
+	}
+
+	r.NextPage = 0
+	if next, ok := parseNext(p.Next, r.Page); ok {
+		r.NextPage = next
+		return
+	}
+
+	// Without an explicit next marker, a full page that has not reached the total means more data.
+	// Pages are assumed to start at 1; with 0-based pages this costs one extra, empty request.
+	if count == 0 || (r.Limit > 0 && count < r.Limit) {
+		return
+	}
+	// More items than requested means the server ignored the limit and returned everything
+	if r.Limit > 0 && count > r.Limit {
+		return
+	}
+	if r.Total > 0 && r.Limit > 0 && (r.Page-1)*r.Limit+count >= r.Total {
+		return
+	}
+	if r.Limit > 0 || r.Total > 0 {
+		r.NextPage = r.Page + 1
+	}
+}
+
+// parseNext interprets the next field of a list response. It reports false when the field is absent.
+func parseNext(raw json.RawMessage, page int) (int, bool) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return 0, false
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return 0, false
+	}
+
+	switch next := value.(type) {
+	case nil:
+		return 0, true
+	case bool:
+		if next {
+			return page + 1, true
+		}
+		return 0, true
+	case float64:
+		return int(next), true
+	case string:
+		if next == "" {
+			return 0, true
+		}
+		if n, err := strconv.Atoi(next); err == nil {
+			return n, true
+		}
+		if u, err := url.Parse(next); err == nil {
+			if n, err := strconv.Atoi(u.Query().Get("page")); err == nil {
+				return n, true
+			}
+		}
+		return page + 1, true
+	}
+	return 0, false
+}
+
+// parseRate reads the X-RateLimit-* (or IETF RateLimit-*) and Retry-After headers
+func parseRate(h http.Header) Rate {
+	var rate Rate
+	rate.Limit, _ = strconv.Atoi(firstHeader(h, "X-RateLimit-Limit", "RateLimit-Limit"))
+	rate.Remaining, _ = strconv.Atoi(firstHeader(h, "X-RateLimit-Remaining", "RateLimit-Remaining"))
+
+	if reset, err := strconv.ParseInt(firstHeader(h, "X-RateLimit-Reset", "RateLimit-Reset"), 10, 64); err == nil {
+		// Large values are epoch seconds, small ones are seconds from now
+		if reset > 1e9 {
+			rate.Reset = time.Unix(reset, 0)
+		} else {
+			rate.Reset = time.Now().Add(time.Duration(reset) * time.Second)
+		}
+	}
+	rate.RetryAfter = parseRetryAfter(h, 0)
+
+	return rate
+}
+
+// firstHeader returns the first non-empty value among the given header keys
+func firstHeader(h http.Header, keys ...string) string {
+	for _, key := range keys {
+		if value := h.Get(key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,108 @@
+package request_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/internal/request"
+)
+
+func TestResponseSetPagination(t *testing.T) {
+	testCases := []struct {
+		name         string
+		query        string
+		pagination   request.Pagination
+		count        int
+		expectedNext int
+	}{
+		{
+			name:         "ExplicitNextPage",
+			query:        "page=1&limit=10",
+			pagination:   request.Pagination{Next: json.RawMessage(`2`)},
+			count:        10,
+			expectedNext: 2,
+		},
+		{
+			name:         "NextIsNull",
+			query:        "page=3&limit=10",
+			pagination:   request.Pagination{Next: json.RawMessage(`null`)},
+			count:        10,
+			expectedNext: 0,
+		},
+		{
+			name:         "NextIsURL",
+			query:        "page=1&limit=10",
+			pagination:   request.Pagination{Next: json.RawMessage(`"/api/v1/stacks?page=2&limit=10"`)},
+			count:        10,
+			expectedNext: 2,
+		},
+		{
+			name:         "TotalNotReached",
+			query:        "page=1&limit=10",
+			pagination:   request.Pagination{Total: 25},
+			count:        10,
+			expectedNext: 2,
+		},
+		{
+			name:         "TotalReached",
+			query:        "page=3&limit=10",
+			pagination:   request.Pagination{Total: 25},
+			count:        5,
+			expectedNext: 0,
+		},
+		{
+			name:         "ShortPageWithoutTotal",
+			query:        "page=1&limit=10",
+			count:        4,
+			expectedNext: 0,
+		},
+		{
+			name:         "LimitIgnoredByServer",
+			query:        "page=1&limit=10",
+			count:        25,
+			expectedNext: 0,
+		},
+		{
+			name:         "NoPaginationAtAll",
+			count:        4,
+			expectedNext: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var response *request.Response
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Correlation-Id", "corr-1")
+				w.Header().Set("X-RateLimit-Remaining", "42")
+			}))
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			client := &request.Client{BaseURL: serverURL, HTTPClient: server.Client()}
+			req, err := client.NewRequest(context.Background(), http.MethodGet, "/list?"+tc.query, nil)
+			if err != nil {
+				t.Fatalf("NewRequest failed: %v", err)
+			}
+			response, err = client.Do(context.Background(), req, nil)
+			if err != nil {
+				t.Fatalf("Do returned error: %v", err)
+			}
+
+			response.SetPagination(tc.pagination, tc.count)
+			if response.NextPage != tc.expectedNext {
+				t.Errorf("Expected next page %d, got %d", tc.expectedNext, response.NextPage)
+			}
+			if response.CorrelationID != "corr-1" {
+				t.Errorf("Expected correlation ID corr-1, got %q", response.CorrelationID)
+			}
+			if response.Rate.Remaining != 42 {
+				t.Errorf("Expected 42 remaining requests, got %d", response.Rate.Remaining)
+			}
+		})
+	}
+}
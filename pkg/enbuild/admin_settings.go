@@ -0,0 +1,113 @@
+package enbuild
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/internal/request"
+)
+
+// AdminSettingsService reads the settings of the installation
+type AdminSettingsService interface {
+	ListAdminSettings(ctx context.Context) ([]*AdminSettings, error)
+	GetAdminSettings(ctx context.Context) (*AdminSettings, error)
+}
+
+// adminSettingsService implements AdminSettingsService on the user service
+type adminSettingsService struct {
+	client *request.Client
+}
+
+var _ AdminSettingsService = (*adminSettingsService)(nil)
+
+// ListAdminSettings returns every admin settings document of the installation.
+func (s *adminSettingsService) ListAdminSettings(ctx context.Context) ([]*AdminSettings, error) {
+	req, err := s.client.NewRequest(ctx, http.MethodGet, "adminSettings", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if _, err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+	return decodeAdminSettings(resp.Data)
+}
+
+// GetAdminSettings returns the admin settings in effect: the first document configuring a usable
+// authentication mechanism, or else the first document. It returns an error matching ErrNotFound
+// when the installation has none. The admin settings API is read-only.
+func (s *adminSettingsService) GetAdminSettings(ctx context.Context) (*AdminSettings, error) {
+	settings, err := s.ListAdminSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	active := activeAdminSettings(settings)
+	if active == nil {
+		return nil, fmt.Errorf("getting admin settings: %w", ErrNotFound)
+	}
+	return active, nil
+}
+
+// activeAdminSettings returns the first document configuring a usable authentication mechanism,
+// or else the first document, or nil when there is none
+func activeAdminSettings(settings []*AdminSettings) *AdminSettings {
+	for _, s := range settings {
+		if s == nil {
+			continue
+		}
+		switch s.AuthMechanism {
+		case AuthMechanismKeycloak:
+			if s.AdminConfigs.Keycloak.BackendURL != "" {
+				return s
+			}
+		case AuthMechanismLocal:
+			return s
+		}
+	}
+	for _, s := range settings {
+		if s != nil {
+			return s
+		}
+	}
+	return nil
+}
+
+// ServerInfo describes how an ENBUILD installation is configured
+type ServerInfo struct {
+	// BaseURL is the enbuild API URL the client talks to
+	BaseURL       string
+	AuthMechanism string
+	// Keycloak is set when AuthMechanism is AuthMechanismKeycloak
+	Keycloak *KeycloakSettings
+	// VCSIntegrations names the configured VCS integrations, e.g. github
+	VCSIntegrations []string
+	FeatureFlags    map[string]bool
+	// Settings is the admin settings document the information comes from
+	Settings *AdminSettings
+}
+
+// ServerInfo returns how the installation the client talks to is configured, read from its admin settings
+func (c *Client) ServerInfo(ctx context.Context) (*ServerInfo, error) {
+	settings, err := c.AdminSettings.GetAdminSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &ServerInfo{
+		BaseURL:         c.httpClient.BaseURL.String(),
+		AuthMechanism:   settings.AuthMechanism,
+		VCSIntegrations: settings.AdminConfigs.VCSIntegrations(),
+		FeatureFlags:    settings.FeatureFlags,
+		Settings:        settings,
+	}
+	if settings.AuthMechanism == AuthMechanismKeycloak {
+		keycloak := settings.AdminConfigs.Keycloak
+		info.Keycloak = &keycloak
+	}
+	return info, nil
+}
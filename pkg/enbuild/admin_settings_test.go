@@ -0,0 +1,86 @@
+package enbuild
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const testAdminSettingsJSON = `{
+	"_id": "settings1",
+	"authMechanism": "keycloak",
+	"adminConfigs": {
+		"keycloak": {"KEYCLOAK_BACKEND_URL": "https://sso.acme.io", "KEYCLOAK_CLIENT_ID": "enbuild", "KEYCLOAK_REALM": "acme"},
+		"gitlab": {"enabled": true, "url": "https://gitlab.acme.io", "group": "platform"},
+		"sonarqube": {"url": "https://sonar.acme.io"}
+	},
+	"featureFlags": {"mlDatasets": true},
+	"theme": "dark"
+}`
+
+func TestDecodeAdminSettings(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"SingleDocument", testAdminSettingsJSON},
+		{"Array", `[` + testAdminSettingsJSON + `]`},
+		{"KeyedObject", `{"0": ` + testAdminSettingsJSON + `}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings, err := decodeAdminSettings([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("decodeAdminSettings returned error: %v", err)
+			}
+			if len(settings) != 1 {
+				t.Fatalf("Expected 1 settings document, got %d", len(settings))
+			}
+			s := settings[0]
+			if s.AdminConfigs.Keycloak.Realm != "acme" || s.AdminConfigs.GitLab.Group != "platform" {
+				t.Errorf("Unexpected admin configs %+v", s.AdminConfigs)
+			}
+		})
+	}
+}
+
+func TestDecodeAdminSettingsKeyOrder(t *testing.T) {
+	data := `{"10": {"_id": "s10"}, "b": {"_id": "sb"}, "2": {"_id": "s2"}, "a": {"_id": "sa"}}`
+
+	settings, err := decodeAdminSettings([]byte(data))
+	if err != nil {
+		t.Fatalf("decodeAdminSettings returned error: %v", err)
+	}
+	var ids []string
+	for _, s := range settings {
+		ids = append(ids, s.ID.String())
+	}
+	if want := []string{"s2", "s10", "sa", "sb"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Expected documents in order %v, got %v", want, ids)
+	}
+}
+
+func TestServerInfo(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != userAPIPath+"adminSettings" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"data": {"0": {"authMechanism": "none"}, "1": ` + testAdminSettingsJSON + `}}`))
+	}))
+
+	info, err := client.ServerInfo(context.Background())
+	if err != nil {
+		t.Fatalf("ServerInfo returned error: %v", err)
+	}
+	if info.AuthMechanism != AuthMechanismKeycloak || info.Keycloak == nil || info.Keycloak.BackendURL != "https://sso.acme.io" {
+		t.Errorf("Expected the keycloak settings to be picked, got %+v", info)
+	}
+	if !reflect.DeepEqual(info.VCSIntegrations, []string{"gitlab"}) {
+		t.Errorf("Expected the gitlab integration, got %v", info.VCSIntegrations)
+	}
+	if !info.FeatureFlags["mlDatasets"] {
+		t.Errorf("Expected feature flags, got %v", info.FeatureFlags)
+	}
+}
@@ -11,14 +11,20 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/internal/request"
 )
 
-// AdminSettingsResponse represents the response from the admin settings API
+// AdminSettingsResponse represents the response from the admin settings API.
+//
+// Deprecated: use Client.AdminSettings, which returns the typed AdminSettings.
 type AdminSettingsResponse struct {
 	Data map[string]AdminSettingData `json:"data"`
 }
 
-// AdminSettingData represents the admin settings data
+// AdminSettingData represents the admin settings data.
+//
+// Deprecated: use AdminSettings.
 type AdminSettingData struct {
 	AuthMechanism string `json:"authMechanism"`
 	AdminConfigs  struct {
@@ -61,6 +67,10 @@ type AuthManager struct {
 	debug          bool
 	baseURL        string
 	authMechanism  string
+
+	// client, when set, fetches the admin settings from the user service with the
+	// retries, rate limiting and logging of the Client
+	client *request.Client
 }
 
 // NewAuthManager creates a new AuthManager
@@ -110,15 +120,16 @@ func (am *AuthManager) Initialize(ctx context.Context) error {
 	return nil
 }
 
-// fetchAdminSettings retrieves the authentication configuration from the admin settings API
-func (am *AuthManager) fetchAdminSettings(ctx context.Context) error {
+// requestAdminSettings fetches the admin settings documents directly, for auth managers
+// created without a client for the user service
+func (am *AuthManager) requestAdminSettings(ctx context.Context) ([]*AdminSettings, error) {
 	// Construct the admin settings API URL
 	baseURL := am.baseURL
 
 	// Extract the domain from the base URL
 	parsedURL, err := url.Parse(baseURL)
 	if err != nil {
-		return fmt.Errorf("failed to parse base URL: %v", err)
+		return nil, fmt.Errorf("failed to parse base URL: %v", err)
 	}
 
 	// For development environment, use enbuild-dev.vivplatform.io
@@ -129,8 +140,8 @@ func (am *AuthManager) fetchAdminSettings(ctx context.Context) error {
 
 	// Construct the admin settings URL using the appropriate domain
 	// According to the requirements, the path should be /enbuild-user/api/v1/adminSettings
-	adminSettingsURL := fmt.Sprintf("%s://%s/enbuild-user/api/v1/adminSettings",
-		parsedURL.Scheme, host)
+	adminSettingsURL := fmt.Sprintf("%s://%s%sadminSettings",
+		parsedURL.Scheme, host, userAPIPath)
 
 	if am.debug {
 		fmt.Printf("DEBUG: Fetching auth config from: %s\n", adminSettingsURL)
@@ -139,7 +150,7 @@ func (am *AuthManager) fetchAdminSettings(ctx context.Context) error {
 	// Make the request to the admin settings API
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, adminSettingsURL, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create request for admin settings: %w", err)
+		return nil, fmt.Errorf("failed to create request for admin settings: %w", err)
 	}
 
 	client := &http.Client{
@@ -147,42 +158,66 @@ func (am *AuthManager) fetchAdminSettings(ctx context.Context) error {
 	}
 	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("Failed to fetch authMechanism from ENBUILD. Please check ENBUILD_BASE_URL or network connectivity: %v", err)
+		return nil, fmt.Errorf("Failed to fetch authMechanism from ENBUILD. Please check ENBUILD_BASE_URL or network connectivity: %v", err)
 	}
 	defer resp.Body.Close()
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read admin settings response: %v", err)
+		return nil, fmt.Errorf("failed to read admin settings response: %v", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("Failed to fetch authMechanism from ENBUILD. API returned status code %d: %s",
+		return nil, fmt.Errorf("Failed to fetch authMechanism from ENBUILD. API returned status code %d: %s",
 			resp.StatusCode, string(bodyBytes))
 	}
 
 	// Parse the response
-	var adminSettings AdminSettingsResponse
+	var adminSettings struct {
+		Data json.RawMessage `json:"data"`
+	}
 	if err := json.Unmarshal(bodyBytes, &adminSettings); err != nil {
-		return fmt.Errorf("failed to parse admin settings: %v", err)
+		return nil, fmt.Errorf("failed to parse admin settings: %v", err)
+	}
+	settings, err := decodeAdminSettings(adminSettings.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse admin settings: %v", err)
+	}
+	return settings, nil
+}
+
+// fetchAdminSettings retrieves the authentication configuration from the admin settings API
+func (am *AuthManager) fetchAdminSettings(ctx context.Context) error {
+	var settings []*AdminSettings
+	if am.client != nil {
+		if am.debug {
+			fmt.Printf("DEBUG: Fetching auth config from: %sadminSettings\n", am.client.BaseURL)
+		}
+		var err error
+		settings, err = (&adminSettingsService{client: am.client}).ListAdminSettings(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed to fetch authMechanism from ENBUILD. Please check ENBUILD_BASE_URL, the user service URL or network connectivity: %v", err)
+		}
+	} else {
+		var err error
+		settings, err = am.requestAdminSettings(ctx)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Extract the authentication configuration
 	configFound := false
-	for _, setting := range adminSettings.Data {
+	if setting := activeAdminSettings(settings); setting != nil {
 		am.authMechanism = setting.AuthMechanism
 
-		if am.authMechanism == "keycloak" && setting.AdminConfigs.Keycloak.KeycloakBackendURL != "" {
-			am.keycloakConfig.BackendURL = setting.AdminConfigs.Keycloak.KeycloakBackendURL
-			// juned
-			am.keycloakConfig.ClientID = setting.AdminConfigs.Keycloak.KeycloakClientID
-			// am.keycloakConfig.ClientID = "enbuild-ui"
-			am.keycloakConfig.Realm = setting.AdminConfigs.Keycloak.KeycloakRealm
+		if am.authMechanism == AuthMechanismKeycloak && setting.AdminConfigs.Keycloak.BackendURL != "" {
+			am.keycloakConfig.BackendURL = setting.AdminConfigs.Keycloak.BackendURL
+			am.keycloakConfig.ClientID = setting.AdminConfigs.Keycloak.ClientID
+			am.keycloakConfig.Realm = setting.AdminConfigs.Keycloak.Realm
 			configFound = true
-			break
-		} else if am.authMechanism == "local" {
+		} else if am.authMechanism == AuthMechanismLocal {
 			configFound = true
-			break
 		}
 	}
 
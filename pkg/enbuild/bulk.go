@@ -0,0 +1,143 @@
+package enbuild
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// defaultBulkConcurrency is the number of items RunBulk processes at once by default
+const defaultBulkConcurrency = 4
+
+// BulkOptions configures RunBulk and the bulk methods built on it
+type BulkOptions struct {
+	// Concurrency is the number of items processed at once (default 4)
+	Concurrency int
+	// StopOnError stops starting new items after the first failure. Items already running
+	// complete and the remaining ones are reported as BulkSkipped.
+	StopOnError bool
+	// DryRun reports every item as BulkDryRun without processing it
+	DryRun bool
+}
+
+// BulkStatus is the outcome of a single item of a bulk operation
+type BulkStatus string
+
+const (
+	BulkSucceeded BulkStatus = "succeeded"
+	BulkFailed    BulkStatus = "failed"
+	// BulkSkipped items were not processed because an earlier item failed with StopOnError set
+	BulkSkipped BulkStatus = "skipped"
+	// BulkDryRun items would have been processed without DryRun
+	BulkDryRun BulkStatus = "dry-run"
+)
+
+// BulkItemResult is the outcome of a single item of a bulk operation
+type BulkItemResult struct {
+	Status BulkStatus
+	// Err is set when Status is BulkFailed
+	Err error
+}
+
+// BulkResults maps each item ID of a bulk operation to its outcome
+type BulkResults map[string]BulkItemResult
+
+// IDs returns the sorted IDs of the items with the given status
+func (r BulkResults) IDs(status BulkStatus) []string {
+	var ids []string
+	for id, result := range r {
+		if result.Status == status {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Err joins the errors of the failed items, naming each item, or returns nil when none failed
+func (r BulkResults) Err() error {
+	var errs []error
+	for _, id := range r.IDs(BulkFailed) {
+		errs = append(errs, fmt.Errorf("%s: %w", id, r[id].Err))
+	}
+	return errors.Join(errs...)
+}
+
+// RunBulk calls fn for every ID, running at most opts.Concurrency calls at once, and returns the
+// outcome of every ID. Duplicate IDs are processed once. Once ctx is done, the items not started
+// yet fail with its error.
+func RunBulk(ctx context.Context, ids []string, opts BulkOptions, fn func(ctx context.Context, id string) error) BulkResults {
+	results := make(BulkResults, len(ids))
+	if opts.DryRun {
+		for _, id := range ids {
+			results[id] = BulkItemResult{Status: BulkDryRun}
+		}
+		return results
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBulkConcurrency
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		stopped bool
+	)
+	record := func(id string, result BulkItemResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		results[id] = result
+		if result.Status == BulkFailed && opts.StopOnError {
+			stopped = true
+		}
+	}
+
+	slots := make(chan struct{}, concurrency)
+	for _, id := range ids {
+		mu.Lock()
+		_, seen := results[id]
+		if !seen {
+			// Reserve the ID so duplicates are not processed twice
+			results[id] = BulkItemResult{Status: BulkSkipped}
+		}
+		halt := stopped
+		mu.Unlock()
+		if seen || halt {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			record(id, BulkItemResult{Status: BulkFailed, Err: ctx.Err()})
+			continue
+		case slots <- struct{}{}:
+		}
+
+		// Another item may have failed while waiting for a slot
+		mu.Lock()
+		halt = stopped
+		mu.Unlock()
+		if halt {
+			<-slots
+			continue
+		}
+
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			if err := fn(ctx, id); err != nil {
+				record(id, BulkItemResult{Status: BulkFailed, Err: err})
+				return
+			}
+			record(id, BulkItemResult{Status: BulkSucceeded})
+		}(id)
+	}
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,79 @@
+package enbuild
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunBulk(t *testing.T) {
+	errBoom := errors.New("boom")
+	ctx := context.Background()
+
+	t.Run("BoundsConcurrency", func(t *testing.T) {
+		var running, peak atomic.Int32
+		ids := []string{"a", "b", "c", "d", "e", "f", "a"}
+		var mu sync.Mutex
+		calls := make(map[string]int)
+
+		results := RunBulk(ctx, ids, BulkOptions{Concurrency: 2}, func(ctx context.Context, id string) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			mu.Lock()
+			calls[id]++
+			mu.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			if id == "c" {
+				return errBoom
+			}
+			return nil
+		})
+
+		if peak.Load() > 2 {
+			t.Errorf("Expected at most 2 concurrent calls, got %d", peak.Load())
+		}
+		if calls["a"] != 1 {
+			t.Errorf("Expected duplicate IDs to be processed once, got %d calls", calls["a"])
+		}
+		if len(results.IDs(BulkSucceeded)) != 5 {
+			t.Errorf("Expected 5 successes, got %v", results.IDs(BulkSucceeded))
+		}
+		if results["c"].Status != BulkFailed || !errors.Is(results.Err(), errBoom) {
+			t.Errorf("Expected c to fail with boom, got %+v", results["c"])
+		}
+	})
+
+	t.Run("StopOnError", func(t *testing.T) {
+		results := RunBulk(ctx, []string{"a", "b", "c"}, BulkOptions{Concurrency: 1, StopOnError: true}, func(ctx context.Context, id string) error {
+			if id == "a" {
+				return errBoom
+			}
+			return nil
+		})
+		if results["a"].Status != BulkFailed {
+			t.Errorf("Expected a to fail, got %+v", results["a"])
+		}
+		if skipped := results.IDs(BulkSkipped); len(skipped) != 2 {
+			t.Errorf("Expected b and c to be skipped, got %v", skipped)
+		}
+	})
+
+	t.Run("DryRun", func(t *testing.T) {
+		results := RunBulk(ctx, []string{"a", "b"}, BulkOptions{DryRun: true}, func(ctx context.Context, id string) error {
+			t.Errorf("Unexpected call for %s", id)
+			return nil
+		})
+		if len(results.IDs(BulkDryRun)) != 2 || results.Err() != nil {
+			t.Errorf("Unexpected dry run results %+v", results)
+		}
+	})
+}
@@ -3,108 +3,144 @@ package enbuild
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
-	"strings"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/internal/request"
 )
 
-// List returns a list of catalogs.
-func (s *Enbuild) ListCatalog(ctx context.Context, opts ...*CatalogListOptions) ([]*Catalog, error) {
+// CatalogsService manages catalogs, the templates stacks are deployed from
+type CatalogsService interface {
+	ListCatalog(ctx context.Context, opts ...*CatalogListOptions) ([]*Catalog, error)
+	ListCatalogWithResponse(ctx context.Context, opts *CatalogListOptions) ([]*Catalog, *Response, error)
+	GetCatalog(ctx context.Context, id string, opts *CatalogListOptions) (*Catalog, error)
+	CreateCatalog(ctx context.Context, input *CatalogInput) (*Catalog, error)
+	UpdateCatalog(ctx context.Context, id string, input *CatalogInput) (*Catalog, error)
+	PatchCatalog(ctx context.Context, id string, input *CatalogPatchInput) (*Catalog, error)
+	DeleteCatalog(ctx context.Context, id string) error
+	AllCatalogs(ctx context.Context, opts *CatalogListOptions) iter.Seq2[*Catalog, error]
+	ListAllCatalogs(ctx context.Context, opts *CatalogListOptions) ([]*Catalog, error)
+}
+
+// catalogsService implements CatalogsService on the enbuild API
+type catalogsService struct {
+	client *request.Client
+}
+
+var _ CatalogsService = (*catalogsService)(nil)
+
+// ListCatalog returns a list of catalogs.
+// The filters in opts are sent to the manifests endpoint as query parameters and,
+// for servers that ignore them, applied again to the response. See CatalogListOptions.
+func (s *catalogsService) ListCatalog(ctx context.Context, opts ...*CatalogListOptions) ([]*Catalog, error) {
 	var options *CatalogListOptions
-	if len(opts) > 0 && opts[0] != nil {
+	if len(opts) > 0 {
 		options = opts[0]
-	} else {
-		// If options are for query params, this should be nil for a GET request body.
-		// If options ARE the body, then this is fine.
-		// For now, preserving original logic of passing it as body.
-		options = &CatalogListOptions{}
 	}
 
-	// Assuming 'options' is intended as the body. If it's for query params,
-	// path should be constructed dynamically and body should be nil.
-	req, err := s.client.NewRequest(ctx, http.MethodGet, "manifests", options)
+	catalogs, _, err := s.ListCatalogWithResponse(ctx, options)
+	return catalogs, err
+}
+
+// ListCatalogWithResponse returns a list of catalogs along with the response metadata.
+// Pagination values describe the server response, before filters are applied locally.
+func (s *catalogsService) ListCatalogWithResponse(ctx context.Context, opts *CatalogListOptions) ([]*Catalog, *Response, error) {
+	catalogs, response, err := listPage[*Catalog](ctx, s.client, "manifests", opts)
 	if err != nil {
-		return nil, err
+		return nil, response, err
 	}
+	return s.filterCatalogs(catalogs, opts), response, nil
+}
 
-	var resp struct {
-		Data []*Catalog `json:"data"`
-	}
-	if _, err := s.client.Do(ctx, req, &resp); err != nil {
+// GetCatalog returns a single catalog by ID.
+// It returns an error matching ErrNotFound when the catalog does not exist.
+func (s *catalogsService) GetCatalog(ctx context.Context, id string, opts *CatalogListOptions) (*Catalog, error) {
+	path, err := request.AddQuery(fmt.Sprintf("manifests/%s", id), opts)
+	if err != nil {
 		return nil, err
 	}
+	return getByID(ctx, s.client, "catalog", path, id, func(c *Catalog) ID { return c.ID })
+}
 
-	for _, catalog := range resp.Data {
-		if id, ok := catalog.ID.(float64); ok {
-			catalog.ID = fmt.Sprintf("%v", int64(id))
-		} else if id, ok := catalog.ID.(string); ok {
-			catalog.ID = id
-		}
+// CreateCatalog publishes a new catalog and returns it as stored by the server.
+// The input is validated before it is sent; server-side validation failures are returned as *APIError.
+func (s *catalogsService) CreateCatalog(ctx context.Context, input *CatalogInput) (*Catalog, error) {
+	if input == nil {
+		return nil, fmt.Errorf("catalog input is required")
 	}
-
-	return s.filterCatalogs(resp.Data, options), nil
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+	return writeItem[Catalog](ctx, s.client, "catalog", http.MethodPost, "manifests", input)
 }
 
-// Get returns a single catalog by ID.
-func (s *Enbuild) GetCatalog(ctx context.Context, id string, opts *CatalogListOptions) (*Catalog, error) {
+// UpdateCatalog replaces the catalog with the given ID and returns it as stored by the server.
+func (s *catalogsService) UpdateCatalog(ctx context.Context, id string, input *CatalogInput) (*Catalog, error) {
 	if id == "" {
 		return nil, fmt.Errorf("catalog ID is required")
 	}
-
-	// Assuming 'opts' is intended as the body.
-	path := fmt.Sprintf("manifests/%s", id)
-	req, err := s.client.NewRequest(ctx, http.MethodGet, path, opts)
-	if err != nil {
+	if input == nil {
+		return nil, fmt.Errorf("catalog input is required")
+	}
+	if err := input.validate(); err != nil {
 		return nil, err
 	}
+	return writeItem[Catalog](ctx, s.client, "catalog", http.MethodPut, fmt.Sprintf("manifests/%s", id), input)
+}
 
-	var resp struct {
-		Data []*Catalog `json:"data"`
+// PatchCatalog changes the fields set in input on the catalog with the given ID
+// and returns it as stored by the server.
+func (s *catalogsService) PatchCatalog(ctx context.Context, id string, input *CatalogPatchInput) (*Catalog, error) {
+	if id == "" {
+		return nil, fmt.Errorf("catalog ID is required")
+	}
+	if input == nil {
+		return nil, fmt.Errorf("catalog patch input is required")
 	}
-	if _, err := s.client.Do(ctx, req, &resp); err != nil {
+	if err := input.validate(); err != nil {
 		return nil, err
 	}
+	return writeItem[Catalog](ctx, s.client, "catalog", http.MethodPatch, fmt.Sprintf("manifests/%s", id), input)
+}
 
-	for _, catalog := range resp.Data {
-		if id, ok := catalog.ID.(float64); ok {
-			catalog.ID = fmt.Sprintf("%v", int64(id))
-		} else if id, ok := catalog.ID.(string); ok {
-			catalog.ID = id
-		}
+// DeleteCatalog deletes a catalog by ID.
+func (s *catalogsService) DeleteCatalog(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("catalog ID is required")
 	}
 
-	return resp.Data[0], nil
+	req, err := s.client.NewRequest(ctx, http.MethodDelete, fmt.Sprintf("manifests/%s", id), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// AllCatalogs returns an iterator over the catalogs of every page.
+// A zero opts.Limit uses the default page size, see ListOptions.
+func (s *catalogsService) AllCatalogs(ctx context.Context, opts *CatalogListOptions) iter.Seq2[*Catalog, error] {
+	return allItems(ctx, opts, s.ListCatalogWithResponse)
+}
+
+// ListAllCatalogs returns the catalogs of every page.
+func (s *catalogsService) ListAllCatalogs(ctx context.Context, opts *CatalogListOptions) ([]*Catalog, error) {
+	return listAllItems(ctx, opts, s.ListCatalogWithResponse)
 }
 
-func (s *Enbuild) filterCatalogs(catalogs []*Catalog, opts *CatalogListOptions) []*Catalog {
-	if opts == nil {
+// filterCatalogs applies the filters of opts locally, for servers that ignore the query filters.
+// On servers that honor them it keeps every catalog. Nil entries are dropped.
+func (s *catalogsService) filterCatalogs(catalogs []*Catalog, opts *CatalogListOptions) []*Catalog {
+	if !opts.hasFilters() {
 		return catalogs
 	}
 
-	var filtered []*Catalog
+	filtered := make([]*Catalog, 0, len(catalogs))
 	for _, m := range catalogs {
-		if opts.ID != "" && !strings.EqualFold(m.ID.(string), opts.ID) {
-			continue
-		}
-		if opts.VCS != "" && !strings.EqualFold(m.VCS, opts.VCS) {
-			continue
-		}
-		if opts.Type != "" && !strings.EqualFold(m.Type, opts.Type) {
-			continue
+		if m != nil && opts.Matches(m) {
+			filtered = append(filtered, m)
 		}
-		if opts.Slug != "" && !strings.EqualFold(m.Slug, opts.Slug) {
-			continue
-		}
-		if opts.Name != "" && !strings.Contains(strings.ToLower(m.Name), strings.ToLower(opts.Name)) {
-			continue
-		}
-		if opts.Description != "" && !strings.Contains(strings.ToLower(m.Description), strings.ToLower(opts.Description)) {
-			continue
-		}
-		if opts.Version != "" && !strings.EqualFold(m.Version, opts.Version) {
-			continue
-		}
-
-		filtered = append(filtered, m)
 	}
 
 	return filtered
@@ -0,0 +1,217 @@
+package enbuild
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestListCatalogSendsQuery(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected GET, got %s", r.Method)
+		}
+		if r.ContentLength > 0 {
+			t.Errorf("Expected no request body, got %d bytes", r.ContentLength)
+		}
+		expectedPath := apiVersionPath + "manifests?vcs=github&type=terraform"
+		if r.URL.String() != expectedPath {
+			t.Errorf("Expected path %s, got %s", expectedPath, r.URL.String())
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": []map[string]interface{}{
+				{"_id": "1", "name": "EKS", "vcs": "github", "type": "terraform"},
+				{"_id": "2", "name": "AKS", "vcs": "gitlab", "type": "terraform"},
+			},
+		})
+	}))
+
+	catalogs, err := client.Catalogs.ListCatalog(context.Background(), &CatalogListOptions{VCS: "github", Type: "terraform"})
+	if err != nil {
+		t.Fatalf("ListCatalog returned error: %v", err)
+	}
+	if len(catalogs) != 1 || catalogs[0].Name != "EKS" {
+		t.Errorf("Expected only the github catalog, got %+v", catalogs)
+	}
+}
+
+func TestCreateCatalog(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != apiVersionPath+"manifests" {
+			t.Errorf("Expected POST manifests, got %s %s", r.Method, r.URL.Path)
+		}
+		var input CatalogInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Fatalf("Failed to decode body: %v", err)
+		}
+		if input.Slug == "duplicate" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"statusCode":400,"message":["slug must be unique","ref is invalid"],"error":"Bad Request"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"_id": 42, "name": input.Name, "slug": input.Slug, "vcs": input.VCS, "type": input.Type},
+		})
+	}))
+	ctx := context.Background()
+
+	catalog, err := client.Catalogs.CreateCatalog(ctx, &CatalogInput{Name: "EKS", Slug: "eks", Type: "terraform", VCS: "github"})
+	if err != nil {
+		t.Fatalf("CreateCatalog returned error: %v", err)
+	}
+	if catalog.ID != "42" || catalog.Slug != "eks" {
+		t.Errorf("Unexpected catalog %+v", catalog)
+	}
+
+	_, err = client.Catalogs.CreateCatalog(ctx, &CatalogInput{Name: "EKS"})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Problems) != 3 {
+		t.Errorf("Expected a ValidationError with 3 problems, got %v", err)
+	}
+
+	_, err = client.Catalogs.CreateCatalog(ctx, &CatalogInput{Name: "EKS", Slug: "duplicate", Type: "terraform", VCS: "github"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an APIError, got %v", err)
+	}
+	if !apiErr.IsValidation() || len(apiErr.Messages) != 2 || apiErr.Messages[0] != "slug must be unique" {
+		t.Errorf("Unexpected validation error %+v", apiErr)
+	}
+}
+
+func TestGetCatalogDecodesTypedModel(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": [{
+			"_id": "6638a128d6852d0012a27491",
+			"name": "EKS",
+			"slug": "eks",
+			"vcs": "gitlab",
+			"version": "1.2.0",
+			"components": [{"name": "Cluster", "slug": "cluster", "mandatory": true, "id": 1}],
+			"infrastructure": {"slug": "aws", "selections": [{"slug": "aws", "name": "AWS", "fields": [{"name": "Access Key", "type": "password", "variable": "AWS_ACCESS_KEY_ID", "required": true}]}]},
+			"configuration": [{"id": "general", "name": "General", "fields": [{"name": "Region", "variable": "region", "defaultValue": "us-east-1", "type": "text"}]}],
+			"content": {"legacy": true},
+			"futureField": 7
+		}]}`))
+	}))
+
+	catalog, err := client.Catalogs.GetCatalog(context.Background(), "6638a128d6852d0012a27491", nil)
+	if err != nil {
+		t.Fatalf("GetCatalog returned error: %v", err)
+	}
+
+	if component, ok := catalog.Component("cluster"); !ok || !component.Mandatory {
+		t.Errorf("Expected mandatory cluster component, got %+v", catalog.Components)
+	}
+	if selection, ok := catalog.InfraSelection("aws"); !ok || len(selection.Fields) != 1 || !selection.Fields[0].Required {
+		t.Errorf("Unexpected infrastructure %+v", catalog.Infrastructure)
+	}
+	if field, ok := catalog.ConfigField("region"); !ok || field.DefaultValue != "us-east-1" {
+		t.Errorf("Unexpected configuration %+v", catalog.Configuration)
+	}
+	if catalog.Version != "1.2.0" {
+		t.Errorf("Expected version 1.2.0, got %q", catalog.Version)
+	}
+	if len(catalog.Extra) != 2 || string(catalog.Extra["futureField"]) != "7" {
+		t.Errorf("Expected content and futureField in Extra, got %v", catalog.Extra)
+	}
+}
+
+func TestCatalogListOptionsMatches(t *testing.T) {
+	catalog := &Catalog{
+		CatalogData: CatalogData{ID: "6638a128d6852d0012a27491", Name: "Big Bang on EKS", Description: "Platform One baseline", VCS: "GitLab", Type: "terraform", Slug: "bigbang-eks"},
+		Version:     "2.1.0",
+	}
+
+	testCases := []struct {
+		name     string
+		opts     *CatalogListOptions
+		expected bool
+	}{
+		{name: "NilOptions", opts: nil, expected: true},
+		{name: "NoFilters", opts: &CatalogListOptions{}, expected: true},
+		{name: "VCSIgnoresCase", opts: &CatalogListOptions{VCS: "gitlab"}, expected: true},
+		{name: "VCSMismatch", opts: &CatalogListOptions{VCS: "github"}, expected: false},
+		{name: "TypeExact", opts: &CatalogListOptions{Type: "TERRAFORM"}, expected: true},
+		{name: "SlugIsNotSubstring", opts: &CatalogListOptions{Slug: "bigbang"}, expected: false},
+		{name: "NameSubstringIgnoresCase", opts: &CatalogListOptions{Name: "bang"}, expected: true},
+		{name: "NameMismatch", opts: &CatalogListOptions{Name: "aks"}, expected: false},
+		{name: "DescriptionSubstring", opts: &CatalogListOptions{Description: "platform one"}, expected: true},
+		{name: "Version", opts: &CatalogListOptions{Version: "2.1.0"}, expected: true},
+		{name: "IDIgnoresCase", opts: &CatalogListOptions{ID: "6638A128D6852D0012A27491"}, expected: true},
+		{name: "AllFiltersMustMatch", opts: &CatalogListOptions{VCS: "gitlab", Version: "1.0.0"}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.opts.Matches(catalog); got != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestListCatalogFiltersByIDWithoutPanicking(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := r.URL.Query().Get("id"); id != "2" {
+			t.Errorf("Expected id filter in query, got %q", id)
+		}
+		// A server ignoring the filter, with a catalog missing its ID and a null entry
+		w.Write([]byte(`{"data": [{"name": "no id"}, null, {"_id": 2, "name": "numeric id"}]}`))
+	}))
+
+	catalogs, err := client.Catalogs.ListCatalog(context.Background(), &CatalogListOptions{ID: "2"})
+	if err != nil {
+		t.Fatalf("ListCatalog returned error: %v", err)
+	}
+	if len(catalogs) != 1 || catalogs[0].Name != "numeric id" {
+		t.Errorf("Expected only the catalog with ID 2, got %+v", catalogs)
+	}
+}
+
+func TestGetCatalogResponses(t *testing.T) {
+	testCases := []struct {
+		name         string
+		status       int
+		body         string
+		expectedName string
+		expectedErr  error
+	}{
+		{name: "EmptyArray", status: http.StatusOK, body: `{"data": []}`, expectedErr: ErrNotFound},
+		{name: "NullData", status: http.StatusOK, body: `{"data": null}`, expectedErr: ErrNotFound},
+		{name: "MissingData", status: http.StatusOK, body: `{}`, expectedErr: ErrNotFound},
+		{name: "NotFoundStatus", status: http.StatusNotFound, body: `{"statusCode": 404, "message": "Manifest not found", "error": "Not Found"}`, expectedErr: ErrNotFound},
+		{name: "SingleElementArray", status: http.StatusOK, body: `{"data": [{"_id": "abc", "name": "EKS"}]}`, expectedName: "EKS"},
+		{name: "Object", status: http.StatusOK, body: `{"data": {"_id": "abc", "name": "EKS"}}`, expectedName: "EKS"},
+		{name: "MultiElementPicksMatchingID", status: http.StatusOK, body: `{"data": [{"_id": "xyz", "name": "AKS"}, {"_id": "abc", "name": "EKS"}]}`, expectedName: "EKS"},
+		{name: "MultiElementWithoutMatch", status: http.StatusOK, body: `{"data": [{"_id": 1, "name": "AKS"}, {"_id": 2, "name": "GKE"}]}`, expectedErr: ErrNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != apiVersionPath+"manifests/abc" {
+					t.Errorf("Unexpected path %s", r.URL.Path)
+				}
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			}))
+
+			catalog, err := client.Catalogs.GetCatalog(context.Background(), "abc", nil)
+			if tc.expectedErr != nil {
+				if !errors.Is(err, tc.expectedErr) {
+					t.Fatalf("Expected error %v, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetCatalog returned error: %v", err)
+			}
+			if catalog.Name != tc.expectedName {
+				t.Errorf("Expected catalog %s, got %s", tc.expectedName, catalog.Name)
+			}
+		})
+	}
+}
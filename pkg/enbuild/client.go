@@ -6,7 +6,6 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"strings"
 	"time"
 
 	"github.com/vivsoftorg/enbuild-sdk-go/internal/request"
@@ -15,34 +14,76 @@ import (
 const (
 	defaultBaseURL    = "https://enbuild.vivplatform.io"
 	defaultTimeout    = 30 * time.Second
+	defaultMaxRetries = 3
 	apiVersionPath    = "/enbuild-bk/api/v1/"
-	adminSettingsPath = "/enbuild-user/api/v1/adminSettings"
+	userAPIPath       = "/enbuild-user/api/v1/"
+	mlAPIPath         = "/enbuild-ml/api/v1/"
 )
 
-// Enbuild handles communication with the enbuild-api endpoints.
-type Enbuild struct {
-	client *request.Client
-}
-
 // Client represents the ENBUILD API client
 type Client struct {
 	httpClient  *request.Client
+	userClient  *request.Client
+	mlClient    *request.Client
 	authManager *AuthManager
 
-	// Enbuilds
-	Catalogs *Enbuild
-	Stacks   *Enbuild
+	// rootURL is the URL the services are served under unless serviceURLs overrides them
+	rootURL     *url.URL
+	serviceURLs map[Service]*url.URL
+
+	// credentials set by WithKeycloakAuth, used once every option is applied
+	username string
+	password string
+
+	// Services, one per resource. Tests may replace them with fakes.
+	Catalogs      CatalogsService
+	Stacks        StacksService
+	Operations    OperationsService
+	Repositories  RepositoriesService
+	Users         UsersService
+	Roles         RolesService
+	AdminSettings AdminSettingsService
+	MLDatasets    MLDatasetsService
+}
+
+// Enbuild handles communication with the enbuild-api endpoints.
+//
+// Deprecated: use the services of Client, e.g. Client.Stacks. Enbuild will be removed in the next release.
+type Enbuild struct {
+	*catalogsService
+	*stacksService
 }
 
+// CircuitBreakerSettings configures the optional circuit breaker, see WithCircuitBreaker
+type CircuitBreakerSettings = request.CircuitBreakerSettings
+
+// CircuitState is the state of the circuit kept for a single host
+type CircuitState = request.CircuitState
+
+// Circuit breaker states reported to CircuitBreakerSettings.OnStateChange
+const (
+	CircuitClosed   = request.CircuitClosed
+	CircuitOpen     = request.CircuitOpen
+	CircuitHalfOpen = request.CircuitHalfOpen
+)
+
 // ClientOption is a function that configures a Client
 type ClientOption func(ctx context.Context, c *Client) error
 
-// NewEnbuild creates a new enbuild api Enbuild.
+// NewEnbuild creates a new enbuild api Enbuild sending the catalog and stack calls through client.
+//
+// Deprecated: use NewClient and its services. NewEnbuild will be removed in the next release.
 func NewEnbuild(client *request.Client) *Enbuild {
-	return &Enbuild{client: client}
+	catalogs := &catalogsService{client: client}
+	return &Enbuild{
+		catalogsService: catalogs,
+		stacksService:   &stacksService{client: client, catalogs: catalogs},
+	}
 }
 
-// NewClient creates a new ENBUILD API client
+// NewClient creates a new ENBUILD API client.
+// Every service is routed to its base URL, see WithServiceURL, and authentication
+// is initialized once all options are applied.
 func NewClient(ctx context.Context, options ...ClientOption) (*Client, error) {
 	// Get base URL from environment variable if provided
 	baseURLEnv := os.Getenv("ENBUILD_BASE_URL")
@@ -51,22 +92,23 @@ func NewClient(ctx context.Context, options ...ClientOption) (*Client, error) {
 		baseURLToUse = baseURLEnv
 	}
 
-	// Process the base URL to ensure it has the API version path
-	if !strings.HasSuffix(baseURLToUse, strings.TrimPrefix(apiVersionPath, "/")) &&
-		!strings.Contains(baseURLToUse, apiVersionPath) {
-		baseURLToUse += apiVersionPath
+	rootURL, err := parseRootURL(baseURLToUse)
+	if err != nil {
+		return nil, err
 	}
-
-	baseURL, _ := url.Parse(baseURLToUse)
 	httpClient := &request.Client{
-		BaseURL:    baseURL,
 		UserAgent:  "enbuild-sdk-go",
 		HTTPClient: &http.Client{Timeout: defaultTimeout},
 		Debug:      false,
+		// An unlimited rate limiter still backs off when the server answers 429 with Retry-After
+		RateLimiter: request.NewRateLimiter(0, 1),
+		MaxRetries:  defaultMaxRetries,
 	}
 
 	c := &Client{
-		httpClient: httpClient,
+		httpClient:  httpClient,
+		rootURL:     rootURL,
+		serviceURLs: make(map[Service]*url.URL),
 	}
 
 	// Apply options
@@ -76,10 +118,19 @@ func NewClient(ctx context.Context, options ...ClientOption) (*Client, error) {
 		}
 	}
 
-	// If no token provider was set and no auth manager exists, use default credentials
-	if c.httpClient.TokenProvider == nil && c.authManager == nil {
-		username := os.Getenv("ENBUILD_USERNAME")
-		password := os.Getenv("ENBUILD_PASSWORD")
+	// Route the services. The user and ML clients are copies of the enbuild API client,
+	// sharing its HTTP client, retries, rate limiter, circuit breaker and debug logging.
+	c.httpClient.BaseURL = c.serviceURL(ServiceEnbuild)
+	c.userClient = c.serviceClient(ServiceUser)
+	c.mlClient = c.serviceClient(ServiceML)
+
+	// If no token provider was set, log in with the configured or default credentials
+	if c.httpClient.TokenProvider == nil {
+		username, password := c.username, c.password
+		if username == "" || password == "" {
+			username = os.Getenv("ENBUILD_USERNAME")
+			password = os.Getenv("ENBUILD_PASSWORD")
+		}
 
 		// If environment variables are not set, use default credentials
 		if username == "" || password == "" {
@@ -91,8 +142,9 @@ func NewClient(ctx context.Context, options ...ClientOption) (*Client, error) {
 			password = "juned"
 		}
 
-		// Create auth manager with credentials
-		authManager := NewAuthManager(username, password, c.httpClient.Debug, c.httpClient.BaseURL.String())
+		// Create auth manager with credentials, reading the auth configuration from the user service
+		authManager := NewAuthManager(username, password, c.httpClient.Debug, c.userClient.BaseURL.String())
+		authManager.client = c.userClient
 		if err := authManager.Initialize(ctx); err != nil {
 			return nil, fmt.Errorf("failed to initialize authentication: %v", err)
 		}
@@ -112,34 +164,36 @@ func NewClient(ctx context.Context, options ...ClientOption) (*Client, error) {
 			return token
 		}
 	}
-
-	// Initialize Enbuilds
-	c.Catalogs = NewEnbuild(c.httpClient)
-	c.Stacks = NewEnbuild(c.httpClient)
+	c.userClient.TokenProvider = c.httpClient.TokenProvider
+	c.mlClient.TokenProvider = c.httpClient.TokenProvider
+
+	// Initialize services
+	catalogs := &catalogsService{client: c.httpClient}
+	c.Catalogs = catalogs
+	c.Stacks = &stacksService{client: c.httpClient, catalogs: catalogs}
+	c.Operations = &operationsService{client: c.httpClient}
+	c.Repositories = &repositoriesService{client: c.httpClient}
+	c.Users = &usersService{client: c.userClient}
+	c.Roles = &rolesService{client: c.userClient}
+	c.AdminSettings = &adminSettingsService{client: c.userClient}
+	c.MLDatasets = &mlDatasetsService{client: c.mlClient}
 
 	return c, nil
 }
 
-// WithBaseURL sets a custom base URL for the API
+// WithBaseURL sets the root URL of the ENBUILD installation, e.g. https://enbuild.example.com.
+// Every service is served under it unless overridden with WithServiceURL. A URL ending with
+// the enbuild API path, as accepted by earlier versions, is reduced to its root.
 func WithBaseURL(baseURL string) ClientOption {
 	return func(ctx context.Context, c *Client) error {
-		// Ensure the URL ends with the API version path
-		if !strings.HasSuffix(baseURL, strings.TrimPrefix(apiVersionPath, "/")) &&
-			!strings.Contains(baseURL, apiVersionPath) {
-			baseURL += apiVersionPath
-		}
-
-		// Parse the URL
-		parsedURL, err := url.Parse(baseURL)
+		rootURL, err := parseRootURL(baseURL)
 		if err != nil {
-			return fmt.Errorf("invalid base URL: %v", err)
+			return err
 		}
-
-		// Update the client's base URL
-		c.httpClient.BaseURL = parsedURL
+		c.rootURL = rootURL
 
 		if c.httpClient.Debug {
-			fmt.Printf("Using base URL: %s\n", baseURL)
+			fmt.Printf("Using base URL: %s\n", rootURL)
 		}
 
 		return nil
@@ -154,32 +208,61 @@ func WithTimeout(timeout time.Duration) ClientOption {
 	}
 }
 
-// WithKeycloakAuth sets the Keycloak authentication credentials
-func WithKeycloakAuth(username, password string) ClientOption {
+// WithRateLimit limits the client to rps requests per second, allowing bursts of up to burst requests.
+// Waiting for a slot honors the request context.
+func WithRateLimit(rps float64, burst int) ClientOption {
 	return func(ctx context.Context, c *Client) error {
-		// Create auth manager with provided credentials
-		authManager := NewAuthManager(username, password, c.httpClient.Debug, c.httpClient.BaseURL.String())
+		if rps <= 0 {
+			return fmt.Errorf("rate limit must be positive, got %v", rps)
+		}
+		c.httpClient.RateLimiter = request.NewRateLimiter(rps, burst)
+		return nil
+	}
+}
 
-		// Initialize the auth manager
-		if err := authManager.Initialize(ctx); err != nil {
-			return fmt.Errorf("failed to initialize authentication: %v", err)
+// WithMaxConcurrentRequests caps the number of requests the client has in flight at the same time
+func WithMaxConcurrentRequests(n int) ClientOption {
+	return func(ctx context.Context, c *Client) error {
+		if n < 1 {
+			return fmt.Errorf("max concurrent requests must be at least 1, got %d", n)
 		}
+		c.httpClient.Semaphore = request.NewSemaphore(n)
+		return nil
+	}
+}
 
-		// Store the auth manager in the client
-		c.authManager = authManager
+// WithMaxRetries sets how many times a request answered with 429 Too Many Requests is retried
+func WithMaxRetries(retries int) ClientOption {
+	return func(ctx context.Context, c *Client) error {
+		if retries < 0 {
+			return fmt.Errorf("max retries must not be negative, got %d", retries)
+		}
+		c.httpClient.MaxRetries = retries
+		return nil
+	}
+}
 
-		// Set the token provider to use the auth manager
-		c.httpClient.TokenProvider = func(requestCtx context.Context) string {
-			token, err := authManager.GetToken(requestCtx)
-			if err != nil {
-				if c.httpClient.Debug {
-					fmt.Printf("Error getting token: %v\n", err)
-				}
-				return ""
-			}
-			return token
+// WithCircuitBreaker makes the client fail fast with ErrCircuitOpen while a host keeps failing,
+// instead of waiting for the full timeout on every request
+func WithCircuitBreaker(settings CircuitBreakerSettings) ClientOption {
+	return func(ctx context.Context, c *Client) error {
+		if settings.FailureRatio < 0 || settings.FailureRatio > 1 {
+			return fmt.Errorf("failure ratio must be between 0 and 1, got %v", settings.FailureRatio)
 		}
+		c.httpClient.CircuitBreaker = request.NewCircuitBreaker(settings)
+		return nil
+	}
+}
 
+// WithKeycloakAuth sets the Keycloak authentication credentials.
+// The login happens once every option is applied, against the user service.
+func WithKeycloakAuth(username, password string) ClientOption {
+	return func(ctx context.Context, c *Client) error {
+		if username == "" || password == "" {
+			return fmt.Errorf("username and password are required")
+		}
+		c.username = username
+		c.password = password
 		return nil
 	}
 }
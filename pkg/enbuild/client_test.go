@@ -0,0 +1,43 @@
+package enbuild
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestClient creates a Client talking to an httptest server running handler.
+// It installs a static token provider so NewClient skips the Keycloak login.
+func newTestClient(t *testing.T, handler http.Handler, options ...ClientOption) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	withTestToken := func(ctx context.Context, c *Client) error {
+		c.httpClient.TokenProvider = func(context.Context) string { return "test-token" }
+		return nil
+	}
+
+	options = append([]ClientOption{WithBaseURL(server.URL), withTestToken}, options...)
+	client, err := NewClient(context.Background(), options...)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return client
+}
+
+func TestNewEnbuild(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != apiVersionPath+"stacks/s1" {
+			t.Errorf("Expected the stack path, got %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"data": {"_id": "s1"}}`))
+	}))
+
+	stack, err := NewEnbuild(client.httpClient).GetStack(context.Background(), "s1")
+	if err != nil || stack.ID != "s1" {
+		t.Errorf("Expected stack s1, got %+v, %v", stack, err)
+	}
+}
@@ -0,0 +1,55 @@
+package enbuild
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/internal/request"
+)
+
+// ErrCircuitOpen is returned without contacting the server while the circuit breaker for its host is open
+var ErrCircuitOpen = request.ErrCircuitOpen
+
+// ErrNotFound is matched by errors.Is when a resource does not exist, whether the server
+// answered 404 or returned no data
+var ErrNotFound = request.ErrNotFound
+
+// APIError is returned when the server answers with a status outside the 2xx range.
+// Use errors.As to inspect the status code and the server's validation messages.
+type APIError = request.APIError
+
+// ValidationError is returned when a request is rejected by the SDK before it is sent
+type ValidationError struct {
+	// Problems lists every invalid or missing field
+	Problems []string
+}
+
+// Error lists the validation problems
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("validation failed: %s", strings.Join(e.Problems, "; "))
+}
+
+// validator collects validation problems
+type validator struct {
+	problems []string
+}
+
+// require records a problem when value is empty
+func (v *validator) require(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.problems = append(v.problems, fmt.Sprintf("%s is required", field))
+	}
+}
+
+// addf records a problem
+func (v *validator) addf(format string, args ...interface{}) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+// err returns a ValidationError when problems were recorded
+func (v *validator) err() error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: v.problems}
+}
@@ -0,0 +1,45 @@
+package enbuild
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// NewTestClient lets the tests of package enbuild_test create a Client talking to an httptest server
+var NewTestClient = newTestClient
+
+// TestCatalogJSON is a catalog with a mandatory and an optional component,
+// two infrastructure selections and two configuration fields
+const TestCatalogJSON = `{
+	"_id": "cat1",
+	"name": "EKS",
+	"slug": "eks",
+	"type": "terraform",
+	"multi_select": true,
+	"components": [
+		{"name": "Cluster", "slug": "cluster", "mandatory": true, "id": 1},
+		{"name": "Monitoring", "slug": "monitoring", "id": 2}
+	],
+	"infrastructure": {"slug": "cloud", "selections": [
+		{"slug": "aws", "name": "AWS", "fields": [
+			{"name": "Access Key", "type": "text", "variable": "AWS_ACCESS_KEY_ID", "required": true, "plaintext": true},
+			{"name": "Secret Key", "type": "password", "variable": "AWS_SECRET_ACCESS_KEY", "required": true},
+			{"name": "Session Token", "type": "text", "variable": "AWS_SESSION_TOKEN"}
+		]},
+		{"slug": "azure", "name": "Azure", "fields": []}
+	]},
+	"configuration": [{"id": "general", "name": "General", "fields": [
+		{"name": "Region", "variable": "region", "defaultValue": "us-east-1", "type": "text"},
+		{"name": "Admin Password", "variable": "admin_password", "type": "password"}
+	]}]
+}`
+
+// DecodeTestCatalog decodes TestCatalogJSON
+func DecodeTestCatalog(t *testing.T) *Catalog {
+	t.Helper()
+	var catalog Catalog
+	if err := json.Unmarshal([]byte(TestCatalogJSON), &catalog); err != nil {
+		t.Fatalf("Failed to decode test catalog: %v", err)
+	}
+	return &catalog
+}
@@ -0,0 +1,83 @@
+package enbuild
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// knownFieldsCache maps a struct type to the JSON field names it decodes
+var knownFieldsCache sync.Map
+
+// unknownFields returns the top-level members of the JSON object data that t does not decode.
+// It returns nil when every member is known.
+func unknownFields(data []byte, t reflect.Type) (map[string]json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	known := knownFields(t)
+	for name := range fields {
+		if known[strings.ToLower(name)] {
+			delete(fields, name)
+		}
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// decodeWithExtra decodes the JSON object data into v and returns its members v does not decode,
+// for the Extra field of a model such as Catalog. A model calls it from its UnmarshalJSON with v pointing to a
+// type defined on the model, which has the same fields but not the UnmarshalJSON method.
+func decodeWithExtra[T any](data []byte, v *T) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	return unknownFields(data, reflect.TypeOf(v).Elem())
+}
+
+// knownFields returns the lower-cased JSON names of the fields of t, including embedded structs.
+// Names are lower-cased because encoding/json matches them case-insensitively.
+func knownFields(t reflect.Type) map[string]bool {
+	if cached, ok := knownFieldsCache.Load(t); ok {
+		return cached.(map[string]bool)
+	}
+
+	known := make(map[string]bool)
+	collectFields(t, known)
+	knownFieldsCache.Store(t, known)
+	return known
+}
+
+func collectFields(t reflect.Type, known map[string]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				collectFields(ft, known)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		known[strings.ToLower(name)] = true
+	}
+}
@@ -0,0 +1,46 @@
+package enbuild
+
+import (
+	"context"
+	"iter"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/internal/request"
+)
+
+// MLDatasetsService reads the datasets of the ML service
+type MLDatasetsService interface {
+	ListMLDatasets(ctx context.Context, opts *MLDatasetListOptions) ([]*MLDataset, error)
+	ListMLDatasetsWithResponse(ctx context.Context, opts *MLDatasetListOptions) ([]*MLDataset, *Response, error)
+	AllMLDatasets(ctx context.Context, opts *MLDatasetListOptions) iter.Seq2[*MLDataset, error]
+	ListAllMLDatasets(ctx context.Context, opts *MLDatasetListOptions) ([]*MLDataset, error)
+}
+
+// mlDatasetsService implements MLDatasetsService on the ML service
+type mlDatasetsService struct {
+	client *request.Client
+}
+
+var _ MLDatasetsService = (*mlDatasetsService)(nil)
+
+// ListMLDatasets returns a page of ML datasets matching the filters in opts.
+func (s *mlDatasetsService) ListMLDatasets(ctx context.Context, opts *MLDatasetListOptions) ([]*MLDataset, error) {
+	datasets, _, err := s.ListMLDatasetsWithResponse(ctx, opts)
+	return datasets, err
+}
+
+// ListMLDatasetsWithResponse returns a page of ML datasets along with the response metadata,
+// which carries the pagination totals and the next page.
+func (s *mlDatasetsService) ListMLDatasetsWithResponse(ctx context.Context, opts *MLDatasetListOptions) ([]*MLDataset, *Response, error) {
+	return listPage[*MLDataset](ctx, s.client, "mlDataset", opts)
+}
+
+// AllMLDatasets returns an iterator over the ML datasets of every page.
+// A zero opts.Limit uses the default page size, see ListOptions.
+func (s *mlDatasetsService) AllMLDatasets(ctx context.Context, opts *MLDatasetListOptions) iter.Seq2[*MLDataset, error] {
+	return allItems(ctx, opts, s.ListMLDatasetsWithResponse)
+}
+
+// ListAllMLDatasets returns the ML datasets of every page.
+func (s *mlDatasetsService) ListAllMLDatasets(ctx context.Context, opts *MLDatasetListOptions) ([]*MLDataset, error) {
+	return listAllItems(ctx, opts, s.ListMLDatasetsWithResponse)
+}
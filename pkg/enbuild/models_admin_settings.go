@@ -0,0 +1,133 @@
+package enbuild
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Authentication mechanisms an installation can be configured with
+const (
+	AuthMechanismKeycloak = "keycloak"
+	AuthMechanismLocal    = "local"
+)
+
+// AdminSettings is the settings document configuring an ENBUILD installation
+type AdminSettings struct {
+	ID ID `json:"_id,omitempty"`
+	// AuthMechanism is AuthMechanismKeycloak or AuthMechanismLocal
+	AuthMechanism string       `json:"authMechanism"`
+	AdminConfigs  AdminConfigs `json:"adminConfigs"`
+	// FeatureFlags enable optional features of the UI and services
+	FeatureFlags map[string]bool `json:"featureFlags,omitempty"`
+	UpdatedBy    string          `json:"updatedBy,omitempty"`
+	CreatedOn    Timestamp       `json:"createdOn"`
+	UpdatedOn    Timestamp       `json:"updatedOn"`
+}
+
+// AdminConfigs holds the configuration sections of the admin settings
+type AdminConfigs struct {
+	Keycloak KeycloakSettings `json:"keycloak"`
+	// VCS integrations
+	GitHub    VCSIntegration `json:"github"`
+	GitLab    VCSIntegration `json:"gitlab"`
+	Bitbucket VCSIntegration `json:"bitbucket"`
+}
+
+// KeycloakSettings configures the Keycloak login of an installation
+type KeycloakSettings struct {
+	BackendURL string `json:"KEYCLOAK_BACKEND_URL"`
+	ClientID   string `json:"KEYCLOAK_CLIENT_ID"`
+	Realm      string `json:"KEYCLOAK_REALM"`
+}
+
+// VCSIntegration configures the connection of an installation to a VCS
+type VCSIntegration struct {
+	Enabled bool   `json:"enabled,omitempty"`
+	URL     string `json:"url,omitempty"`
+	// Group is the group or organization stacks are created in
+	Group string `json:"group,omitempty"`
+}
+
+// configured reports whether the integration is enabled or carries any setting
+func (i VCSIntegration) configured() bool {
+	return i.Enabled || i.URL != "" || i.Group != ""
+}
+
+// VCSIntegrations returns the names of the configured VCS integrations, sorted
+func (c *AdminConfigs) VCSIntegrations() []string {
+	var names []string
+	for name, integration := range map[string]VCSIntegration{
+		"bitbucket": c.Bitbucket,
+		"github":    c.GitHub,
+		"gitlab":    c.GitLab,
+	} {
+		if integration.configured() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lessDocumentKey orders the keys of a keyed settings object: numeric keys in numeric order,
+// before any other key, which are ordered as strings
+func lessDocumentKey(a, b string) bool {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return na < nb
+	case errA == nil || errB == nil:
+		return errA == nil
+	}
+	return a < b
+}
+
+// decodeAdminSettings decodes the data of the admin settings endpoint, which holds either
+// a single settings document, an array of them or an object keyed by document
+func decodeAdminSettings(data json.RawMessage) ([]*AdminSettings, error) {
+	data = bytes.TrimSpace(data)
+	switch {
+	case len(data) == 0 || bytes.Equal(data, []byte("null")):
+		return nil, nil
+	case data[0] == '[':
+		var settings []*AdminSettings
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return nil, fmt.Errorf("decoding admin settings: %w", err)
+		}
+		return settings, nil
+	case data[0] != '{':
+		return nil, fmt.Errorf("decoding admin settings: unexpected data %.20s", data)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("decoding admin settings: %w", err)
+	}
+	if _, ok := fields["authMechanism"]; ok {
+		var settings AdminSettings
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return nil, fmt.Errorf("decoding admin settings: %w", err)
+		}
+		return []*AdminSettings{&settings}, nil
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return lessDocumentKey(keys[i], keys[j]) })
+
+	settings := make([]*AdminSettings, 0, len(keys))
+	for _, key := range keys {
+		var s AdminSettings
+		if err := json.Unmarshal(fields[key], &s); err != nil {
+			return nil, fmt.Errorf("decoding admin settings %s: %w", key, err)
+		}
+		settings = append(settings, &s)
+	}
+	return settings, nil
+}
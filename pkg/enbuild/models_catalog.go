@@ -1,21 +1,67 @@
 package enbuild
 
-// Catalog represents an ENBUILD catalog
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Catalog represents an ENBUILD catalog, decoded into the typed CatalogData model.
+// Fields the model does not know about are kept raw in Extra.
 type Catalog struct {
-	ID          interface{}            `json:"_id,omitempty"`
-	Name        string                 `json:"name"`
-	Description string                 `json:"description,omitempty"`
-	Content     map[string]interface{} `json:"content,omitempty"`
-	Version     string                 `json:"version,omitempty"`
-	CreatedOn   interface{}            `json:"createdOn,omitempty"`
-	UpdatedOn   interface{}            `json:"updatedOn,omitempty"`
-	VCS         string                 `json:"vcs,omitempty"`
-	Slug        string                 `json:"slug,omitempty"`
-	Type        string                 `json:"type,omitempty"`
-}
-
-// CatalogListOptions specifies the optional parameters to the catalogsService.List method.
+	CatalogData
+
+	Version string `json:"version,omitempty"`
+
+	// Extra holds the fields of the catalog document that are not part of the typed model
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the typed fields of a catalog and keeps the remaining ones in Extra
+func (c *Catalog) UnmarshalJSON(data []byte) error {
+	type catalog Catalog
+	var decoded catalog
+	extra, err := decodeWithExtra(data, &decoded)
+	if err != nil {
+		return err
+	}
+	decoded.Extra = extra
+
+	*c = Catalog(decoded)
+	return nil
+}
+
+// Info returns the reference to the catalog stored on the stacks deployed from it
+func (c *Catalog) Info() CatalogInfo {
+	return CatalogInfo{ID: c.ID, Slug: c.Slug, Type: c.Type, Name: c.Name}
+}
+
+// findComponent returns the catalog component a stack component selection refers to,
+// matching its name against component slugs and names, or its data against component IDs and names
+func (c *Catalog) findComponent(selection Component) (*ComponentCfg, bool) {
+	for i := range c.Components {
+		cfg := &c.Components[i]
+		if selection.Name != "" && (strings.EqualFold(selection.Name, cfg.Slug) || strings.EqualFold(selection.Name, cfg.Name)) {
+			return cfg, true
+		}
+		for _, data := range selection.Data {
+			if (data.ID != "" && data.ID == cfg.ID) || (data.Name != "" && strings.EqualFold(data.Name, cfg.Name)) {
+				return cfg, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// CatalogListOptions specifies the optional parameters to the ListCatalog methods.
+// Catalogs are only paginated when ListOptions.Limit is set.
+//
+// Filters are combined with AND and all comparisons ignore case. ID, VCS, Type, Slug and Version
+// must match exactly, while Name and Description match when the catalog field contains the value.
+// Empty filters are ignored.
 type CatalogListOptions struct {
+	ListOptions
+
 	ID          string `url:"id,omitempty"`
 	VCS         string `url:"vcs,omitempty"`
 	Type        string `url:"type,omitempty"`
@@ -24,3 +70,137 @@ type CatalogListOptions struct {
 	Description string `url:"description,omitempty"`
 	Version     string `url:"version,omitempty"`
 }
+
+// Matches reports whether a catalog satisfies every filter in o. A nil o matches every catalog.
+func (o *CatalogListOptions) Matches(c *Catalog) bool {
+	if o == nil {
+		return true
+	}
+	if c == nil {
+		return false
+	}
+
+	return matchExact(c.ID.String(), o.ID) &&
+		matchExact(c.VCS, o.VCS) &&
+		matchExact(c.Type, o.Type) &&
+		matchExact(c.Slug, o.Slug) &&
+		matchContains(c.Name, o.Name) &&
+		matchContains(c.Description, o.Description) &&
+		matchExact(c.Version, o.Version)
+}
+
+// hasFilters reports whether any filter is set
+func (o *CatalogListOptions) hasFilters() bool {
+	return o != nil && (o.ID != "" || o.VCS != "" || o.Type != "" || o.Slug != "" ||
+		o.Name != "" || o.Description != "" || o.Version != "")
+}
+
+// matchExact reports whether value equals filter ignoring case, or filter is empty
+func matchExact(value, filter string) bool {
+	return filter == "" || strings.EqualFold(value, filter)
+}
+
+// matchContains reports whether value contains filter ignoring case, or filter is empty
+func matchContains(value, filter string) bool {
+	return filter == "" || strings.Contains(strings.ToLower(value), strings.ToLower(filter))
+}
+
+// CatalogInput is the body of CreateCatalog and UpdateCatalog. Its fields mirror CatalogData,
+// without the ones the server maintains.
+type CatalogInput struct {
+	Type              string              `json:"type"`
+	Slug              string              `json:"slug"`
+	Name              string              `json:"name"`
+	Description       string              `json:"description,omitempty"`
+	VCS               string              `json:"vcs"`
+	Repository        string              `json:"repository,omitempty"`
+	RepositoryId      ID                  `json:"repositoryId,omitempty"`
+	Ref               string              `json:"ref,omitempty"`
+	ReadmeFilePath    string              `json:"readme_file_path,omitempty"`
+	ValuesFolderPath  string              `json:"values_folder_path,omitempty"`
+	SecretsFolderPath string              `json:"secrets_folder_path,omitempty"`
+	ImagePath         string              `json:"image_path,omitempty"`
+	MultiSelect       bool                `json:"multi_select"`
+	Order             int                 `json:"order,omitempty"`
+	Components        []ComponentCfg      `json:"components,omitempty"`
+	Infrastructure    *InfraData          `json:"infrastructure,omitempty"`
+	Configuration     []ConfigurationItem `json:"configuration,omitempty"`
+	DownloadYAML      bool                `json:"download_yaml,omitempty"`
+}
+
+// NewCatalogInput creates a CatalogInput from an existing catalog definition
+func NewCatalogInput(data CatalogData) *CatalogInput {
+	infrastructure := data.Infrastructure
+	return &CatalogInput{
+		Type:              data.Type,
+		Slug:              data.Slug,
+		Name:              data.Name,
+		Description:       data.Description,
+		VCS:               data.VCS,
+		Repository:        data.Repository,
+		RepositoryId:      data.RepositoryId,
+		Ref:               data.Ref,
+		ReadmeFilePath:    data.ReadmeFilePath,
+		ValuesFolderPath:  data.ValuesFolderPath,
+		SecretsFolderPath: data.SecretsFolderPath,
+		ImagePath:         data.ImagePath,
+		MultiSelect:       data.MultiSelect,
+		Order:             data.Order,
+		Components:        data.Components,
+		Infrastructure:    &infrastructure,
+		Configuration:     data.Configuration,
+		DownloadYAML:      data.DownloadYAML,
+	}
+}
+
+// validate checks the fields the manifests endpoint requires
+func (in *CatalogInput) validate() error {
+	var v validator
+	v.require("name", in.Name)
+	v.require("slug", in.Slug)
+	v.require("type", in.Type)
+	v.require("vcs", in.VCS)
+	for i, component := range in.Components {
+		v.require(fmt.Sprintf("components[%d].name", i), component.Name)
+		v.require(fmt.Sprintf("components[%d].slug", i), component.Slug)
+	}
+	return v.err()
+}
+
+// CatalogPatchInput is the body of PatchCatalog. Only the non-nil fields are changed.
+type CatalogPatchInput struct {
+	Type              *string              `json:"type,omitempty"`
+	Slug              *string              `json:"slug,omitempty"`
+	Name              *string              `json:"name,omitempty"`
+	Description       *string              `json:"description,omitempty"`
+	VCS               *string              `json:"vcs,omitempty"`
+	Repository        *string              `json:"repository,omitempty"`
+	RepositoryId      *ID                  `json:"repositoryId,omitempty"`
+	Ref               *string              `json:"ref,omitempty"`
+	ReadmeFilePath    *string              `json:"readme_file_path,omitempty"`
+	ValuesFolderPath  *string              `json:"values_folder_path,omitempty"`
+	SecretsFolderPath *string              `json:"secrets_folder_path,omitempty"`
+	ImagePath         *string              `json:"image_path,omitempty"`
+	MultiSelect       *bool                `json:"multi_select,omitempty"`
+	Order             *int                 `json:"order,omitempty"`
+	Status            *string              `json:"status,omitempty"`
+	Components        *[]ComponentCfg      `json:"components,omitempty"`
+	Infrastructure    *InfraData           `json:"infrastructure,omitempty"`
+	Configuration     *[]ConfigurationItem `json:"configuration,omitempty"`
+	DownloadYAML      *bool                `json:"download_yaml,omitempty"`
+}
+
+// validate rejects patches that would blank out required fields
+func (in *CatalogPatchInput) validate() error {
+	var v validator
+	fields := []struct {
+		name  string
+		value *string
+	}{{"name", in.Name}, {"slug", in.Slug}, {"type", in.Type}, {"vcs", in.VCS}}
+	for _, field := range fields {
+		if field.value != nil {
+			v.require(field.name, *field.value)
+		}
+	}
+	return v.err()
+}
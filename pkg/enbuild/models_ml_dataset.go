@@ -0,0 +1,32 @@
+package enbuild
+
+import ()
+
+// MLDataset is a dataset registered with the ML microservice
+type MLDataset struct {
+	ID          ID     `json:"_id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Type is the kind of data, e.g. tabular or image
+	Type   string `json:"type,omitempty"`
+	Format string `json:"format,omitempty"`
+	// Source is where the data is stored, e.g. an S3 URL
+	Source    string    `json:"source,omitempty"`
+	Size      int64     `json:"size,omitempty"`
+	Version   string    `json:"version,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	CreatedBy string    `json:"createdBy,omitempty"`
+	CreatedOn Timestamp `json:"createdOn"`
+	UpdatedOn Timestamp `json:"updatedOn"`
+}
+
+// MLDatasetListOptions specifies the parameters to the ListMLDatasets methods.
+// Filters are sent as query parameters and applied by the server.
+type MLDatasetListOptions struct {
+	ListOptions
+
+	Name      string    `url:"name,omitempty"`
+	Type      string    `url:"type,omitempty"`
+	CreatedBy string    `url:"createdBy,omitempty"`
+	Sort      SortOrder `url:"sort,omitempty"`
+}
@@ -0,0 +1,52 @@
+package enbuild
+
+import (
+	"encoding/json"
+)
+
+// Operation records an action performed on a stack, such as a deployment, an update or a deletion
+type Operation struct {
+	ID      ID     `json:"_id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Status  string `json:"status,omitempty"`
+	StackID ID     `json:"stackId,omitempty"`
+	Message string `json:"message,omitempty"`
+	// Payload is the input of the operation, as stored by the server
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	CreatedBy string          `json:"createdBy,omitempty"`
+	UpdatedBy string          `json:"updatedBy,omitempty"`
+	CreatedOn Timestamp       `json:"createdOn"`
+	UpdatedOn Timestamp       `json:"updatedOn"`
+}
+
+// OperationListOptions specifies the parameters to the ListOperations methods.
+// Filters are sent as query parameters and applied by the server.
+type OperationListOptions struct {
+	ListOptions
+
+	// StackID keeps the operations of a single stack
+	StackID   ID     `url:"stackId,omitempty"`
+	Type      string `url:"type,omitempty"`
+	Status    string `url:"status,omitempty"`
+	CreatedBy string `url:"createdBy,omitempty"`
+	// Sort orders the operations, the server default is SortDesc("createdOn")
+	Sort SortOrder `url:"sort,omitempty"`
+}
+
+// OperationInput describes an operation to create or the new content of an existing one
+type OperationInput struct {
+	Name    string          `json:"name,omitempty"`
+	Type    string          `json:"type"`
+	Status  string          `json:"status,omitempty"`
+	StackID ID              `json:"stackId,omitempty"`
+	Message string          `json:"message,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// validate checks the fields the operations endpoint requires
+func (in *OperationInput) validate() error {
+	var v validator
+	v.require("type", in.Type)
+	return v.err()
+}
@@ -0,0 +1,31 @@
+package enbuild
+
+import ()
+
+// Repository is a VCS repository holding the infrastructure as code of catalogs and components
+type Repository struct {
+	ID   ID     `json:"_id,omitempty"`
+	Name string `json:"name,omitempty"`
+	// VCS is the hosting service, e.g. github or gitlab
+	VCS string `json:"vcs,omitempty"`
+	URL string `json:"url,omitempty"`
+	// ProjectID identifies the repository in the VCS, e.g. a GitLab project ID
+	ProjectID ID `json:"project_id,omitempty"`
+	// DefaultRef is the branch or tag used when a catalog does not set its own ref
+	DefaultRef string `json:"defaultRef,omitempty"`
+	// CredentialsRef names the stored credentials used to access the repository, never the credentials themselves
+	CredentialsRef string    `json:"credentialsRef,omitempty"`
+	Private        bool      `json:"private,omitempty"`
+	CreatedBy      string    `json:"createdBy,omitempty"`
+	CreatedOn      Timestamp `json:"createdOn"`
+	UpdatedOn      Timestamp `json:"updatedOn"`
+}
+
+// RepositoryListOptions specifies the parameters to the ListRepositories methods
+type RepositoryListOptions struct {
+	ListOptions
+
+	VCS  string    `url:"vcs,omitempty"`
+	Name string    `url:"name,omitempty"`
+	Sort SortOrder `url:"sort,omitempty"`
+}
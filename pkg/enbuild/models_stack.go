@@ -1,7 +1,14 @@
 package enbuild
 
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+)
+
 type Stack struct {
-	ID   string `json:"_id,omitempty"`
+	ID   ID     `json:"_id,omitempty"`
 	Name string `json:"name"`
 	// Stack      StackName      `json:"stack"`
 	Catalog    CatalogInfo `json:"catalog"`
@@ -10,16 +17,102 @@ type Stack struct {
 	Type       string      `json:"type"`
 	CreatedBy  string      `json:"createdBy"`
 	UpdatedBy  string      `json:"updatedBy"`
-	CreatedOn  string      `json:"createdOn,omitempty"`
-	Created_on string      `json:"created_on,omitempty"`
-	UpdatedOn  string      `json:"updatedOn,omitempty"`
+	CreatedOn  Timestamp   `json:"createdOn"`
+	UpdatedOn  Timestamp   `json:"updatedOn"`
 	// V          int            `json:"__v,omitempty"`
-	Logs []StackLog `json:"logs,omitempty"`
-	// Project    *ProjectInfo   `json:"project,omitempty"`
-	// Pipeline   []PipelineItem `json:"pipeline,omitempty"`
-	// Pending    int            `json:"pending,omitempty"`
-	// PermissionsSlug map[string]interface{} `json:"permissionsSlug,omitempty"`
-	// Permissions     map[string]interface{} `json:"permissions,omitempty"`
+	Logs            []StackLog             `json:"logs,omitempty"`
+	Infrastructure  []InfraSelect          `json:"infrastructure,omitempty"`
+	Configuration   map[string]string      `json:"configuration,omitempty"`
+	Project         *ProjectInfo           `json:"project,omitempty"`
+	Pipeline        []PipelineItem         `json:"pipeline,omitempty"`
+	Pending         int                    `json:"pending,omitempty"`
+	PermissionsSlug map[string]interface{} `json:"permissionsSlug,omitempty"`
+	Permissions     map[string]interface{} `json:"permissions,omitempty"`
+}
+
+// LatestPipeline returns the most recently created pipeline of the stack, or nil when it has none
+func (s *Stack) LatestPipeline() *PipelineItem {
+	var latest *PipelineItem
+	for i := range s.Pipeline {
+		p := &s.Pipeline[i]
+		if latest == nil || p.CreatedAt.After(latest.CreatedAt.Time) ||
+			(p.CreatedAt.Equal(latest.CreatedAt.Time) && p.IID > latest.IID) {
+			latest = p
+		}
+	}
+	return latest
+}
+
+// UnmarshalJSON decodes a stack, falling back to the legacy created_on field for CreatedOn
+func (s *Stack) UnmarshalJSON(data []byte) error {
+	type stack Stack
+	var decoded struct {
+		stack
+		LegacyCreatedOn Timestamp `json:"created_on"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	if decoded.CreatedOn.IsZero() {
+		decoded.CreatedOn = decoded.LegacyCreatedOn
+	}
+	*s = Stack(decoded.stack)
+	return nil
+}
+
+// StackListOptions specifies the parameters to the ListStacks methods.
+// Page, Limit and Search are always sent, as the stacks endpoint has always received them;
+// the filters are only sent when set and are applied by the server.
+type StackListOptions struct {
+	ListOptions
+
+	Search string `url:"search,omitempty"`
+
+	// Status and Type keep the stacks with exactly this status or type
+	Status string `url:"status,omitempty"`
+	Type   string `url:"type,omitempty"`
+	// CatalogSlug and CatalogID keep the stacks deployed from this catalog
+	CatalogSlug string `url:"catalog.slug,omitempty"`
+	CatalogID   ID     `url:"catalog.id,omitempty"`
+	// CreatedBy keeps the stacks created by this user
+	CreatedBy string `url:"createdBy,omitempty"`
+	// CreatedAfter and CreatedBefore bound the creation time of the stacks
+	CreatedAfter  time.Time `url:"createdAfter,omitempty"`
+	CreatedBefore time.Time `url:"createdBefore,omitempty"`
+	// Sort orders the stacks, e.g. SortDesc("createdOn"), which is the server default
+	Sort SortOrder `url:"sort,omitempty"`
+}
+
+// Matches reports whether a stack satisfies every filter in o, applying them the way the server
+// is expected to: Search matches part of the name, the other text filters match exactly, ignoring
+// case, and a stack without creation time never satisfies a creation time bound. A nil o matches every stack.
+func (o *StackListOptions) Matches(s *Stack) bool {
+	if o == nil {
+		return true
+	}
+	if s == nil {
+		return false
+	}
+
+	if !o.CreatedAfter.IsZero() && !s.CreatedOn.After(o.CreatedAfter) {
+		return false
+	}
+	if !o.CreatedBefore.IsZero() && (s.CreatedOn.IsZero() || !s.CreatedOn.Before(o.CreatedBefore)) {
+		return false
+	}
+	return matchContains(s.Name, o.Search) &&
+		matchExact(s.Status, o.Status) &&
+		matchExact(s.Type, o.Type) &&
+		matchExact(s.Catalog.Slug, o.CatalogSlug) &&
+		matchExact(s.Catalog.ID.String(), o.CatalogID.String()) &&
+		matchExact(s.CreatedBy, o.CreatedBy)
+}
+
+// hasFilters reports whether any filter is set
+func (o *StackListOptions) hasFilters() bool {
+	return o != nil && (o.Search != "" || o.Status != "" || o.Type != "" || o.CatalogSlug != "" ||
+		o.CatalogID != "" || o.CreatedBy != "" || !o.CreatedAfter.IsZero() || !o.CreatedBefore.IsZero())
 }
 
 type StackName struct {
@@ -27,14 +120,14 @@ type StackName struct {
 }
 
 type CatalogInfo struct {
-	ID   interface{} `json:"id"`
-	Slug string      `json:"slug"`
-	Type string      `json:"type"`
-	Name string      `json:"name"`
+	ID   ID     `json:"id"`
+	Slug string `json:"slug"`
+	Type string `json:"type"`
+	Name string `json:"name"`
 }
 
 type CatalogData struct {
-	ID         string `json:"_id"`
+	ID         ID     `json:"_id,omitempty"`
 	Type       string `json:"type"`
 	Slug       string `json:"slug"`
 	Name       string `json:"name"`
@@ -53,23 +146,55 @@ type CatalogData struct {
 	UpdatedBy      string              `json:"updatedBy"`
 	Order          int                 `json:"order"`
 	Description    string              `json:"description"`
-	RepositoryId   string              `json:"repositoryId"`
+	RepositoryId   ID                  `json:"repositoryId"`
 	Components     []ComponentCfg      `json:"components"`
 	Infrastructure InfraData           `json:"infrastructure"`
 	Configuration  []ConfigurationItem `json:"configuration"`
-	// CreatedOn         string        `json:"createdOn,omitempty"`
-	UpdatedOn    string `json:"updatedOn,omitempty"`
-	DownloadYAML bool   `json:"download_yaml,omitempty"`
+	CreatedOn      Timestamp           `json:"createdOn"`
+	UpdatedOn      Timestamp           `json:"updatedOn"`
+	DownloadYAML   bool                `json:"download_yaml,omitempty"`
+}
+
+// Component returns the component with the given slug
+func (d *CatalogData) Component(slug string) (*ComponentCfg, bool) {
+	for i := range d.Components {
+		if d.Components[i].Slug == slug {
+			return &d.Components[i], true
+		}
+	}
+	return nil, false
+}
+
+// InfraSelection returns the infrastructure selection with the given slug
+func (d *CatalogData) InfraSelection(slug string) (*InfraSelect, bool) {
+	for i := range d.Infrastructure.Selections {
+		if d.Infrastructure.Selections[i].Slug == slug {
+			return &d.Infrastructure.Selections[i], true
+		}
+	}
+	return nil, false
+}
+
+// ConfigField returns the configuration field bound to the given variable
+func (d *CatalogData) ConfigField(variable string) (*ConfigField, bool) {
+	for i := range d.Configuration {
+		for j := range d.Configuration[i].Fields {
+			if d.Configuration[i].Fields[j].Variable == variable {
+				return &d.Configuration[i].Fields[j], true
+			}
+		}
+	}
+	return nil, false
 }
 
 type ConfigurationItem struct {
-	ID     string        `json:"id"`
+	ID     ID            `json:"id"`
 	Name   string        `json:"name"`
 	Fields []ConfigField `json:"fields"`
 }
 
 type ConfigField struct {
-	ID           int    `json:"id,omitempty"`
+	ID           ID     `json:"id,omitempty"`
 	Name         string `json:"name"`
 	Variable     string `json:"variable,omitempty"`
 	DefaultValue string `json:"defaultValue,omitempty"`
@@ -85,18 +210,18 @@ type ComponentCfg struct {
 	VariableFilePath string `json:"variable_file_path"`
 	ImagePath        string `json:"image_path"`
 	Mandatory        bool   `json:"mandatory"`
-	RepositoryId     string `json:"repositoryId"`
+	RepositoryId     ID     `json:"repositoryId"`
 	Ref              string `json:"ref"`
 	Repository       string `json:"repository"`
-	ID               int    `json:"id"`
-	CatalogID        int    `json:"catalog_id"`
+	ID               ID     `json:"id"`
+	CatalogID        ID     `json:"catalog_id"`
 }
 
 type InfraData struct {
 	Slug           string        `json:"slug"`
 	ShowKubeConfig bool          `json:"showKubeConfig"`
 	Selections     []InfraSelect `json:"selections"`
-	CatalogID      int           `json:"catalog_id"`
+	CatalogID      ID            `json:"catalog_id"`
 }
 
 type InfraSelect struct {
@@ -113,6 +238,7 @@ type InfraField struct {
 	Variable  string `json:"variable,omitempty"`
 	Required  bool   `json:"required,omitempty"`
 	Plaintext bool   `json:"plaintext"`
+	Value     string `json:"value,omitempty"`
 }
 
 type Component struct {
@@ -121,7 +247,7 @@ type Component struct {
 }
 
 type ComponentData struct {
-	ID   string `json:"id"`
+	ID   ID     `json:"id"`
 	Name string `json:"name"`
 }
 
@@ -133,26 +259,214 @@ type StackLog struct {
 }
 
 type LogDetail struct {
-	Timestamp int64  `json:"timestamp"`
-	Msg       string `json:"msg"`
-	Level     string `json:"level"`
+	Timestamp Timestamp `json:"timestamp"`
+	Msg       string    `json:"msg"`
+	Level     string    `json:"level"`
 }
 
 type ProjectInfo struct {
-	ID  int    `json:"id"`
+	ID  ID     `json:"id"`
 	URL string `json:"url"`
 }
 
 type PipelineItem struct {
-	ID        int    `json:"id"`
-	IID       int    `json:"iid"`
-	ProjectID int    `json:"project_id"`
-	SHA       string `json:"sha"`
-	Ref       string `json:"ref"`
-	Status    string `json:"status"`
-	Source    string `json:"source"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
-	WebURL    string `json:"web_url"`
-	Name      string `json:"name"`
+	ID        ID        `json:"id"`
+	IID       int       `json:"iid"`
+	ProjectID ID        `json:"project_id"`
+	SHA       string    `json:"sha"`
+	Ref       string    `json:"ref"`
+	Status    string    `json:"status"`
+	Source    string    `json:"source"`
+	CreatedAt Timestamp `json:"created_at"`
+	UpdatedAt Timestamp `json:"updated_at"`
+	WebURL    string    `json:"web_url"`
+	Name      string    `json:"name"`
+}
+
+// CreateStackInput describes a stack to deploy from a catalog
+type CreateStackInput struct {
+	Name    string      `json:"name"`
+	Catalog CatalogInfo `json:"catalog"`
+	// Components are the selected catalog components
+	Components []Component `json:"components"`
+	// Infrastructure holds the chosen infrastructure selection, with field values filled in
+	Infrastructure []InfraSelect `json:"infrastructure,omitempty"`
+	// Configuration maps configuration variables to their values
+	Configuration map[string]string `json:"configuration,omitempty"`
+	Type          string            `json:"type,omitempty"`
+
+	// SkipValidation sends the input without checking it against the catalog first
+	SkipValidation bool `json:"-"`
+}
+
+// NewCreateStackInput prepares a CreateStackInput for catalog, selecting its mandatory
+// components and filling configuration variables with their default values
+func NewCreateStackInput(name string, catalog *Catalog) *CreateStackInput {
+	input := &CreateStackInput{
+		Name:          name,
+		Catalog:       catalog.Info(),
+		Type:          catalog.Type,
+		Configuration: make(map[string]string),
+	}
+
+	for _, component := range catalog.Components {
+		if component.Mandatory {
+			input.SelectComponent(component)
+		}
+	}
+	for _, item := range catalog.Configuration {
+		for _, field := range item.Fields {
+			if field.Variable != "" && field.DefaultValue != "" {
+				input.Configuration[field.Variable] = field.DefaultValue
+			}
+		}
+	}
+
+	return input
+}
+
+// SelectComponent adds a catalog component to the selection
+func (in *CreateStackInput) SelectComponent(component ComponentCfg) {
+	in.Components = append(in.Components, Component{
+		Name: component.Slug,
+		Data: []ComponentData{{ID: component.ID, Name: component.Name}},
+	})
+}
+
+// SelectInfrastructure chooses a catalog infrastructure selection and sets its field values,
+// keyed by field variable or, failing that, by field key or name
+func (in *CreateStackInput) SelectInfrastructure(selection InfraSelect, values map[string]string) {
+	selection.Selected = true
+	selection.Fields = append([]InfraField(nil), selection.Fields...)
+	for i := range selection.Fields {
+		if value, ok := values[selection.Fields[i].fieldKey()]; ok {
+			selection.Fields[i].Value = value
+		}
+	}
+	in.Infrastructure = []InfraSelect{selection}
+}
+
+// Validate checks the input against the catalog it deploys: mandatory components must be selected,
+// selected components must exist, one known infrastructure selection must be chosen with its required
+// fields filled in, and configuration variables must be defined by the catalog.
+func (in *CreateStackInput) Validate(catalog *Catalog) error {
+	var v validator
+	v.require("name", in.Name)
+	if in.Catalog.ID == "" && in.Catalog.Slug == "" {
+		v.addf("catalog ID or slug is required")
+	}
+	if catalog == nil {
+		return v.err()
+	}
+
+	validateSelection(&v, catalog, in.Components, in.Infrastructure, in.Configuration, false)
+	sort.Strings(v.problems)
+	return v.err()
+}
+
+// UpdateStackInput describes changes to an existing stack. Empty fields are left unchanged.
+type UpdateStackInput struct {
+	// Components replaces the selected catalog components
+	Components []Component `json:"components,omitempty"`
+	// Infrastructure replaces the chosen infrastructure selection and its field values
+	Infrastructure []InfraSelect `json:"infrastructure,omitempty"`
+	// Configuration sets the values of configuration variables
+	Configuration map[string]string `json:"configuration,omitempty"`
+
+	// SkipValidation sends the input without checking it against the catalog first
+	SkipValidation bool `json:"-"`
+}
+
+// Validate checks the changes against the catalog of the stack, with the rules of
+// CreateStackInput.Validate applied to the fields that are set
+func (in *UpdateStackInput) Validate(catalog *Catalog) error {
+	var v validator
+	if catalog == nil {
+		return nil
+	}
+
+	validateSelection(&v, catalog, in.Components, in.Infrastructure, in.Configuration, true)
+	sort.Strings(v.problems)
+	return v.err()
+}
+
+// validateSelection checks the components and infrastructure of a stack against its catalog: selected
+// components must exist, mandatory ones must be selected, and exactly one known infrastructure selection
+// must be chosen with its required fields filled in, and configuration variables must be defined by the
+// catalog. With partial set, as for updates, empty components or infrastructure are left unchecked.
+func validateSelection(v *validator, catalog *Catalog, components []Component, infra []InfraSelect, configuration map[string]string, partial bool) {
+	for variable := range configuration {
+		if _, ok := catalog.ConfigField(variable); !ok {
+			v.addf("configuration variable %q is not defined by catalog %s", variable, catalog.Slug)
+		}
+	}
+
+	if !partial || len(components) > 0 {
+		selected := make(map[string]bool)
+		for _, component := range components {
+			cfg, ok := catalog.findComponent(component)
+			if !ok {
+				v.addf("component %q is not part of catalog %s", component.Name, catalog.Slug)
+				continue
+			}
+			selected[cfg.Slug] = true
+		}
+		for _, cfg := range catalog.Components {
+			if cfg.Mandatory && !selected[cfg.Slug] {
+				v.addf("mandatory component %q is not selected", cfg.Slug)
+			}
+		}
+		if !catalog.MultiSelect && len(selected) > 1 {
+			v.addf("catalog %s allows a single component, %d selected", catalog.Slug, len(selected))
+		}
+	}
+
+	if partial && len(infra) == 0 {
+		return
+	}
+	var chosen []InfraSelect
+	for _, selection := range infra {
+		if selection.Selected {
+			chosen = append(chosen, selection)
+		}
+	}
+	switch {
+	case len(chosen) == 1:
+		validateInfraSelection(v, catalog, chosen[0])
+	case len(chosen) > 1:
+		v.addf("only one infrastructure selection may be chosen, %d are", len(chosen))
+	case partial || len(catalog.Infrastructure.Selections) > 0:
+		v.addf("an infrastructure selection is required")
+	}
+}
+
+// validateInfraSelection checks that the chosen selection exists and carries its required values
+func validateInfraSelection(v *validator, catalog *Catalog, chosen InfraSelect) {
+	selection, ok := catalog.InfraSelection(chosen.Slug)
+	if !ok {
+		v.addf("infrastructure %q is not part of catalog %s", chosen.Slug, catalog.Slug)
+		return
+	}
+
+	values := make(map[string]string)
+	for _, field := range chosen.Fields {
+		values[field.fieldKey()] = field.Value
+	}
+	for _, field := range selection.Fields {
+		if field.Required && strings.TrimSpace(values[field.fieldKey()]) == "" {
+			v.addf("infrastructure field %q is required", field.fieldKey())
+		}
+	}
+}
+
+// fieldKey identifies an infrastructure field by variable, key or name
+func (f InfraField) fieldKey() string {
+	switch {
+	case f.Variable != "":
+		return f.Variable
+	case f.Key != "":
+		return f.Key
+	default:
+		return f.Name
+	}
 }
@@ -0,0 +1,80 @@
+package enbuild
+
+import ()
+
+// Roles known to ENBUILD
+const (
+	RoleAdmin   = "admin"
+	RoleAppDev  = "appdev"
+	RoleDataOps = "dataops"
+	RoleDevOps  = "devops"
+)
+
+// User is an ENBUILD user account
+type User struct {
+	ID        ID     `json:"_id,omitempty"`
+	Username  string `json:"username,omitempty"`
+	Email     string `json:"email,omitempty"`
+	FirstName string `json:"firstName,omitempty"`
+	LastName  string `json:"lastName,omitempty"`
+	// Roles are the names of the roles granted to the user, e.g. RoleAdmin
+	Roles     []string  `json:"roles,omitempty"`
+	Enabled   bool      `json:"enabled,omitempty"`
+	CreatedBy string    `json:"createdBy,omitempty"`
+	CreatedOn Timestamp `json:"createdOn"`
+	UpdatedOn Timestamp `json:"updatedOn"`
+}
+
+// HasRole reports whether the user was granted the role, ignoring case
+func (u *User) HasRole(role string) bool {
+	return containsFold(u.Roles, role)
+}
+
+// UserListOptions specifies the parameters to the ListUsers methods
+type UserListOptions struct {
+	ListOptions
+
+	// CreatedBy keeps the users created by this user
+	CreatedBy string `url:"createdBy,omitempty"`
+}
+
+// UserInput describes a user to create or the new content of an existing one
+type UserInput struct {
+	Username  string   `json:"username,omitempty"`
+	Email     string   `json:"email"`
+	FirstName string   `json:"firstName,omitempty"`
+	LastName  string   `json:"lastName,omitempty"`
+	Roles     []string `json:"roles,omitempty"`
+}
+
+// validate checks the fields the users endpoint requires
+func (in *UserInput) validate() error {
+	var v validator
+	v.require("email", in.Email)
+	return v.err()
+}
+
+// Role is a named set of permissions granted to users
+type Role struct {
+	ID          ID     `json:"_id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Permissions maps resources to the actions the role allows on them
+	Permissions map[string]interface{} `json:"permissions,omitempty"`
+	CreatedOn   Timestamp              `json:"createdOn"`
+	UpdatedOn   Timestamp              `json:"updatedOn"`
+}
+
+// RoleInput describes a role to create or the new content of an existing one
+type RoleInput struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Permissions map[string]interface{} `json:"permissions,omitempty"`
+}
+
+// validate checks the fields the roles endpoint requires
+func (in *RoleInput) validate() error {
+	var v validator
+	v.require("name", in.Name)
+	return v.err()
+}
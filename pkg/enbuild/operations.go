@@ -0,0 +1,83 @@
+package enbuild
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/internal/request"
+)
+
+// OperationsService records and reads the operations performed on stacks
+type OperationsService interface {
+	ListOperations(ctx context.Context, opts *OperationListOptions) ([]*Operation, error)
+	ListOperationsWithResponse(ctx context.Context, opts *OperationListOptions) ([]*Operation, *Response, error)
+	GetOperation(ctx context.Context, id string) (*Operation, error)
+	CreateOperation(ctx context.Context, input *OperationInput) (*Operation, error)
+	UpdateOperation(ctx context.Context, id string, input *OperationInput) (*Operation, error)
+	AllOperations(ctx context.Context, opts *OperationListOptions) iter.Seq2[*Operation, error]
+	ListAllOperations(ctx context.Context, opts *OperationListOptions) ([]*Operation, error)
+}
+
+// operationsService implements OperationsService on the enbuild API
+type operationsService struct {
+	client *request.Client
+}
+
+var _ OperationsService = (*operationsService)(nil)
+
+// ListOperations returns a page of operations matching the filters in opts.
+func (s *operationsService) ListOperations(ctx context.Context, opts *OperationListOptions) ([]*Operation, error) {
+	operations, _, err := s.ListOperationsWithResponse(ctx, opts)
+	return operations, err
+}
+
+// ListOperationsWithResponse returns a page of operations along with the response metadata,
+// which carries the pagination totals and the next page.
+func (s *operationsService) ListOperationsWithResponse(ctx context.Context, opts *OperationListOptions) ([]*Operation, *Response, error) {
+	return listPage[*Operation](ctx, s.client, "operations", opts)
+}
+
+// GetOperation returns a single operation by ID.
+// It returns an error matching ErrNotFound when the operation does not exist.
+func (s *operationsService) GetOperation(ctx context.Context, id string) (*Operation, error) {
+	return getByID(ctx, s.client, "operation", fmt.Sprintf("operations/%s", id), id, func(o *Operation) ID { return o.ID })
+}
+
+// CreateOperation records a new operation and returns it as stored by the server.
+func (s *operationsService) CreateOperation(ctx context.Context, input *OperationInput) (*Operation, error) {
+	if input == nil {
+		return nil, fmt.Errorf("operation input is required")
+	}
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+	return writeItem[Operation](ctx, s.client, "operation", http.MethodPost, "operations", input)
+}
+
+// UpdateOperation replaces the operation with the given ID and returns it as stored by the server.
+func (s *operationsService) UpdateOperation(ctx context.Context, id string, input *OperationInput) (*Operation, error) {
+	if id == "" {
+		return nil, fmt.Errorf("operation ID is required")
+	}
+	if input == nil {
+		return nil, fmt.Errorf("operation input is required")
+	}
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+	return writeItem[Operation](ctx, s.client, "operation", http.MethodPut, fmt.Sprintf("operations/%s", id), input)
+}
+
+// AllOperations returns an iterator over the operations of every page, fetching pages as the loop advances.
+// A zero opts.Limit uses the default page size, see ListOptions.
+func (s *operationsService) AllOperations(ctx context.Context, opts *OperationListOptions) iter.Seq2[*Operation, error] {
+	return allItems(ctx, opts, s.ListOperationsWithResponse)
+}
+
+// ListAllOperations returns the operations of every page, e.g. the whole history of a stack
+// when opts.StackID is set.
+func (s *operationsService) ListAllOperations(ctx context.Context, opts *OperationListOptions) ([]*Operation, error) {
+	return listAllItems(ctx, opts, s.ListOperationsWithResponse)
+}
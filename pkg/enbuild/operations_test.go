@@ -0,0 +1,19 @@
+package enbuild
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestCreateOperationValidatesInput(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+	}))
+
+	var validationErr *ValidationError
+	if _, err := client.Operations.CreateOperation(context.Background(), &OperationInput{}); !errors.As(err, &validationErr) {
+		t.Errorf("Expected a ValidationError, got %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package enbuild
+
+import (
+	"context"
+	"iter"
+	"strings"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/internal/request"
+)
+
+// defaultPageLimit is the page size used by every All and ListAll helper when ListOptions.Limit is not set
+const defaultPageLimit = 50
+
+// ListOptions specifies the pagination parameters shared by list endpoints. The All and ListAll
+// helpers of every service page through the results 50 items at a time unless Limit is set.
+type ListOptions struct {
+	Page  int `url:"page,omitempty"`
+	Limit int `url:"limit,omitempty"`
+
+	// Prefetch makes the All and ListAll helpers fetch the next page while the current one is consumed
+	Prefetch bool `url:"-"`
+}
+
+func (o *ListOptions) listOptions() *ListOptions { return o }
+
+// pageable is implemented by pointers to list options embedding ListOptions
+type pageable[O any] interface {
+	*O
+	listOptions() *ListOptions
+}
+
+// pageOptions copies opts, applying the default page size, so the caller's options are not modified while paging
+func pageOptions[O any, P pageable[O]](opts P) O {
+	var options O
+	if opts != nil {
+		options = *opts
+	}
+	if list := P(&options).listOptions(); list.Limit <= 0 {
+		list.Limit = defaultPageLimit
+	}
+	return options
+}
+
+// SortOrder is the sort field and direction of a list request, sent as the sort query
+// parameter: the field name, prefixed with "-" when descending. The zero SortOrder is not sent,
+// leaving the server default, which is -createdOn for most endpoints.
+type SortOrder struct {
+	Field      string
+	Descending bool
+}
+
+// SortAsc sorts by field in ascending order
+func SortAsc(field string) SortOrder {
+	return SortOrder{Field: field}
+}
+
+// SortDesc sorts by field in descending order
+func SortDesc(field string) SortOrder {
+	return SortOrder{Field: field, Descending: true}
+}
+
+// ParseSortOrder parses the "field" and "-field" forms of the sort parameter
+func ParseSortOrder(s string) SortOrder {
+	s = strings.TrimSpace(s)
+	if field, ok := strings.CutPrefix(s, "-"); ok {
+		return SortDesc(field)
+	}
+	return SortAsc(strings.TrimPrefix(s, "+"))
+}
+
+// String returns the sort parameter value
+func (o SortOrder) String() string {
+	if o.Descending && o.Field != "" {
+		return "-" + o.Field
+	}
+	return o.Field
+}
+
+// MarshalText encodes the sort order as a query value
+func (o SortOrder) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+// listFunc lists a page of items with the options P
+type listFunc[T any, P any] func(ctx context.Context, opts P) ([]T, *Response, error)
+
+// allItems returns an iterator over the items of every page listed by list, starting at page 1.
+// opts is copied and a zero Limit uses the default page size.
+func allItems[T any, O any, P pageable[O]](ctx context.Context, opts P, list listFunc[T, P]) iter.Seq2[T, error] {
+	options := pageOptions(opts)
+	pager := &request.Pager[T]{Fetch: pageFetcher(options, list), FirstPage: 1, Prefetch: P(&options).listOptions().Prefetch}
+	return pager.All(ctx)
+}
+
+// listAllItems returns the items of every page listed by list, with the options of allItems
+func listAllItems[T any, O any, P pageable[O]](ctx context.Context, opts P, list listFunc[T, P]) ([]T, error) {
+	options := pageOptions(opts)
+	pager := &request.Pager[T]{Fetch: pageFetcher(options, list), FirstPage: 1, Prefetch: P(&options).listOptions().Prefetch}
+	return pager.Collect(ctx)
+}
+
+// pageFetcher returns a fetcher listing the given page with a copy of options
+func pageFetcher[T any, O any, P pageable[O]](options O, list listFunc[T, P]) request.PageFetcher[T] {
+	return func(ctx context.Context, page int) ([]T, *Response, error) {
+		pageOptions := options
+		P(&pageOptions).listOptions().Page = page
+		return list(ctx, &pageOptions)
+	}
+}
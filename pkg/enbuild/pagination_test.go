@@ -0,0 +1,62 @@
+package enbuild
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestListAllStacks(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if limit := r.URL.Query().Get("limit"); limit != "2" {
+			t.Errorf("Expected limit 2, got %s", limit)
+		}
+
+		var stacks []*Stack
+		for i := 0; i < 2 && (page-1)*2+i < 5; i++ {
+			stacks = append(stacks, &Stack{ID: ID(strconv.Itoa((page-1)*2 + i))})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": stacks, "total": 5})
+	}))
+
+	stacks, err := client.Stacks.ListAllStacks(context.Background(), &StackListOptions{ListOptions: ListOptions{Limit: 2, Prefetch: true}})
+	if err != nil {
+		t.Fatalf("ListAllStacks returned error: %v", err)
+	}
+	if len(stacks) != 5 {
+		t.Fatalf("Expected 5 stacks, got %d", len(stacks))
+	}
+	for i, stack := range stacks {
+		if stack.ID.String() != strconv.Itoa(i) {
+			t.Errorf("Expected stack %d to have ID %d, got %s", i, i, stack.ID)
+		}
+	}
+}
+
+func TestSortOrder(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected SortOrder
+		encoded  string
+	}{
+		{"-createdOn", SortDesc("createdOn"), "-createdOn"},
+		{"name", SortAsc("name"), "name"},
+		{"+name", SortAsc("name"), "name"},
+		{"", SortOrder{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := ParseSortOrder(tt.input)
+			if got != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, got)
+			}
+			if got.String() != tt.encoded {
+				t.Errorf("Expected %q, got %q", tt.encoded, got.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package enbuild
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/internal/request"
+)
+
+// RepositoriesService reads the VCS repositories holding the infrastructure as code of catalogs
+type RepositoriesService interface {
+	ListRepositories(ctx context.Context, opts *RepositoryListOptions) ([]*Repository, error)
+	ListRepositoriesWithResponse(ctx context.Context, opts *RepositoryListOptions) ([]*Repository, *Response, error)
+	GetRepository(ctx context.Context, id string) (*Repository, error)
+	CatalogRepository(ctx context.Context, catalog *Catalog) (*Repository, error)
+	ComponentRepository(ctx context.Context, component *ComponentCfg) (*Repository, error)
+	AllRepositories(ctx context.Context, opts *RepositoryListOptions) iter.Seq2[*Repository, error]
+	ListAllRepositories(ctx context.Context, opts *RepositoryListOptions) ([]*Repository, error)
+}
+
+// repositoriesService implements RepositoriesService on the enbuild API
+type repositoriesService struct {
+	client *request.Client
+}
+
+var _ RepositoriesService = (*repositoriesService)(nil)
+
+// ListRepositories returns a page of repositories matching the filters in opts.
+func (s *repositoriesService) ListRepositories(ctx context.Context, opts *RepositoryListOptions) ([]*Repository, error) {
+	repositories, _, err := s.ListRepositoriesWithResponse(ctx, opts)
+	return repositories, err
+}
+
+// ListRepositoriesWithResponse returns a page of repositories along with the response metadata,
+// which carries the pagination totals and the next page.
+func (s *repositoriesService) ListRepositoriesWithResponse(ctx context.Context, opts *RepositoryListOptions) ([]*Repository, *Response, error) {
+	return listPage[*Repository](ctx, s.client, "repository", opts)
+}
+
+// GetRepository returns a single repository by ID.
+// It returns an error matching ErrNotFound when the repository does not exist.
+func (s *repositoriesService) GetRepository(ctx context.Context, id string) (*Repository, error) {
+	return getByID(ctx, s.client, "repository", fmt.Sprintf("repository/%s", id), id, func(r *Repository) ID { return r.ID })
+}
+
+// CatalogRepository returns the repository referenced by catalog.RepositoryId.
+// It returns an error matching ErrNotFound when the catalog references no repository.
+func (s *repositoriesService) CatalogRepository(ctx context.Context, catalog *Catalog) (*Repository, error) {
+	if catalog == nil || catalog.RepositoryId == "" {
+		return nil, fmt.Errorf("catalog has no repository: %w", ErrNotFound)
+	}
+	return s.GetRepository(ctx, catalog.RepositoryId.String())
+}
+
+// ComponentRepository returns the repository referenced by component.RepositoryId.
+// It returns an error matching ErrNotFound when the component references no repository.
+func (s *repositoriesService) ComponentRepository(ctx context.Context, component *ComponentCfg) (*Repository, error) {
+	if component == nil || component.RepositoryId == "" {
+		return nil, fmt.Errorf("component has no repository: %w", ErrNotFound)
+	}
+	return s.GetRepository(ctx, component.RepositoryId.String())
+}
+
+// AllRepositories returns an iterator over the repositories of every page.
+// A zero opts.Limit uses the default page size, see ListOptions.
+func (s *repositoriesService) AllRepositories(ctx context.Context, opts *RepositoryListOptions) iter.Seq2[*Repository, error] {
+	return allItems(ctx, opts, s.ListRepositoriesWithResponse)
+}
+
+// ListAllRepositories returns the repositories of every page.
+func (s *repositoriesService) ListAllRepositories(ctx context.Context, opts *RepositoryListOptions) ([]*Repository, error) {
+	return listAllItems(ctx, opts, s.ListRepositoriesWithResponse)
+}
@@ -0,0 +1,37 @@
+package enbuild
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestCatalogAndComponentRepository(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != apiVersionPath+"repository/repo1" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"statusCode": 404, "message": "Repository not found"}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"_id": "repo1", "vcs": "gitlab", "url": "https://gitlab.com/acme/iac",
+			"defaultRef": "main", "credentialsRef": "gitlab-token", "project_id": 42}}`))
+	}))
+	ctx := context.Background()
+
+	catalog := &Catalog{CatalogData: CatalogData{RepositoryId: "repo1"}}
+	repository, err := client.Repositories.CatalogRepository(ctx, catalog)
+	if err != nil {
+		t.Fatalf("CatalogRepository returned error: %v", err)
+	}
+	if repository.DefaultRef != "main" || repository.CredentialsRef != "gitlab-token" || repository.ProjectID != "42" {
+		t.Errorf("Unexpected repository %+v", repository)
+	}
+
+	if _, err := client.Repositories.ComponentRepository(ctx, &ComponentCfg{Slug: "cluster"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a component without repository, got %v", err)
+	}
+	if _, err := client.Repositories.ComponentRepository(ctx, &ComponentCfg{RepositoryId: "gone"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing repository, got %v", err)
+	}
+}
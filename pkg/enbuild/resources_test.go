@@ -0,0 +1,270 @@
+package enbuild
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// idsOf returns the IDs of items
+func idsOf[E any](items []*E, idOf func(*E) ID) []string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = idOf(item).String()
+	}
+	return ids
+}
+
+func TestListAllResources(t *testing.T) {
+	testCases := []struct {
+		name string
+		// path is the list endpoint and filter the query parameter set by the options of listAll
+		path    string
+		filter  string
+		listAll func(ctx context.Context, c *Client) ([]string, error)
+	}{
+		{
+			name:   "Operations",
+			path:   apiVersionPath + "operations",
+			filter: "stackId",
+			listAll: func(ctx context.Context, c *Client) ([]string, error) {
+				items, err := c.Operations.ListAllOperations(ctx, &OperationListOptions{ListOptions: ListOptions{Limit: 2}, StackID: "x"})
+				return idsOf(items, func(o *Operation) ID { return o.ID }), err
+			},
+		},
+		{
+			name:   "Repositories",
+			path:   apiVersionPath + "repository",
+			filter: "vcs",
+			listAll: func(ctx context.Context, c *Client) ([]string, error) {
+				items, err := c.Repositories.ListAllRepositories(ctx, &RepositoryListOptions{ListOptions: ListOptions{Limit: 2}, VCS: "x"})
+				return idsOf(items, func(r *Repository) ID { return r.ID }), err
+			},
+		},
+		{
+			name:   "Users",
+			path:   userAPIPath + "users",
+			filter: "createdBy",
+			listAll: func(ctx context.Context, c *Client) ([]string, error) {
+				items, err := c.Users.ListAllUsers(ctx, &UserListOptions{ListOptions: ListOptions{Limit: 2}, CreatedBy: "x"})
+				return idsOf(items, func(u *User) ID { return u.ID }), err
+			},
+		},
+		{
+			name:   "MLDatasets",
+			path:   mlAPIPath + "mlDataset",
+			filter: "type",
+			listAll: func(ctx context.Context, c *Client) ([]string, error) {
+				items, err := c.MLDatasets.ListAllMLDatasets(ctx, &MLDatasetListOptions{ListOptions: ListOptions{Limit: 2}, Type: "x"})
+				return idsOf(items, func(d *MLDataset) ID { return d.ID }), err
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tc.path {
+					t.Errorf("Expected path %s, got %s", tc.path, r.URL.Path)
+				}
+				if r.URL.Query().Get(tc.filter) != "x" || r.URL.Query().Get("limit") != "2" {
+					t.Errorf("Expected the %s filter and the limit to be sent, got %s", tc.filter, r.URL.RawQuery)
+				}
+				if r.URL.Query().Get("page") == "1" {
+					w.Write([]byte(`{"data": [{"_id": "a1"}, {"_id": "a2"}], "total": 3}`))
+					return
+				}
+				w.Write([]byte(`{"data": [{"_id": "a3"}], "total": 3}`))
+			}))
+
+			ids, err := tc.listAll(context.Background(), client)
+			if err != nil {
+				t.Fatalf("Listing returned error: %v", err)
+			}
+			if strings.Join(ids, ",") != "a1,a2,a3" {
+				t.Errorf("Expected a1,a2,a3 over 2 pages, got %v", ids)
+			}
+		})
+	}
+}
+
+func TestGetResources(t *testing.T) {
+	testCases := []struct {
+		name string
+		// path is the endpoint of a resource without its ID
+		path string
+		get  func(ctx context.Context, c *Client, id string) (ID, error)
+	}{
+		{
+			name: "Operation",
+			path: apiVersionPath + "operations/",
+			get: func(ctx context.Context, c *Client, id string) (ID, error) {
+				operation, err := c.Operations.GetOperation(ctx, id)
+				if err != nil {
+					return "", err
+				}
+				return operation.ID, nil
+			},
+		},
+		{
+			name: "Repository",
+			path: apiVersionPath + "repository/",
+			get: func(ctx context.Context, c *Client, id string) (ID, error) {
+				repository, err := c.Repositories.GetRepository(ctx, id)
+				if err != nil {
+					return "", err
+				}
+				return repository.ID, nil
+			},
+		},
+		{
+			name: "User",
+			path: userAPIPath + "users/",
+			get: func(ctx context.Context, c *Client, id string) (ID, error) {
+				user, err := c.Users.GetUser(ctx, id)
+				if err != nil {
+					return "", err
+				}
+				return user.ID, nil
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tc.path+"a1" {
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte(`{"statusCode": 404, "message": "Not found"}`))
+					return
+				}
+				w.Write([]byte(`{"data": {"_id": "a1"}}`))
+			}))
+			ctx := context.Background()
+
+			if id, err := tc.get(ctx, client, "a1"); err != nil || id != "a1" {
+				t.Errorf("Expected a1, got %q, %v", id, err)
+			}
+			if _, err := tc.get(ctx, client, "missing"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Expected ErrNotFound, got %v", err)
+			}
+			if _, err := tc.get(ctx, client, ""); err == nil || errors.Is(err, ErrNotFound) {
+				t.Errorf("Expected an error for an empty ID, got %v", err)
+			}
+		})
+	}
+}
+
+func TestWriteResources(t *testing.T) {
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+		write  func(ctx context.Context, c *Client) (ID, error)
+	}{
+		{
+			name:   "CreateOperation",
+			method: http.MethodPost,
+			path:   apiVersionPath + "operations",
+			write: func(ctx context.Context, c *Client) (ID, error) {
+				operation, err := c.Operations.CreateOperation(ctx, &OperationInput{Type: "create", StackID: "stack1"})
+				if err != nil {
+					return "", err
+				}
+				return operation.ID, nil
+			},
+		},
+		{
+			name:   "UpdateOperation",
+			method: http.MethodPut,
+			path:   apiVersionPath + "operations/w1",
+			write: func(ctx context.Context, c *Client) (ID, error) {
+				operation, err := c.Operations.UpdateOperation(ctx, "w1", &OperationInput{Type: "create", Status: "done"})
+				if err != nil {
+					return "", err
+				}
+				return operation.ID, nil
+			},
+		},
+		{
+			name:   "CreateUser",
+			method: http.MethodPost,
+			path:   userAPIPath + "users",
+			write: func(ctx context.Context, c *Client) (ID, error) {
+				user, err := c.Users.CreateUser(ctx, &UserInput{Email: "dev@acme.io", Roles: []string{RoleDevOps}})
+				if err != nil {
+					return "", err
+				}
+				return user.ID, nil
+			},
+		},
+		{
+			name:   "UpdateUser",
+			method: http.MethodPut,
+			path:   userAPIPath + "users/w1",
+			write: func(ctx context.Context, c *Client) (ID, error) {
+				user, err := c.Users.UpdateUser(ctx, "w1", &UserInput{Email: "dev@acme.io", Roles: []string{RoleAdmin}})
+				if err != nil {
+					return "", err
+				}
+				return user.ID, nil
+			},
+		},
+		{
+			name:   "CreateRole",
+			method: http.MethodPost,
+			path:   userAPIPath + "roles",
+			write: func(ctx context.Context, c *Client) (ID, error) {
+				role, err := c.Roles.CreateRole(ctx, &RoleInput{Name: "auditor"})
+				if err != nil {
+					return "", err
+				}
+				return role.ID, nil
+			},
+		},
+		{
+			name:   "UpdateRole",
+			method: http.MethodPut,
+			path:   userAPIPath + "roles/w1",
+			write: func(ctx context.Context, c *Client) (ID, error) {
+				role, err := c.Roles.UpdateRole(ctx, "w1", &RoleInput{Name: "auditor"})
+				if err != nil {
+					return "", err
+				}
+				return role.ID, nil
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			empty := false
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != tc.method || r.URL.Path != tc.path {
+					t.Errorf("Expected %s %s, got %s %s", tc.method, tc.path, r.Method, r.URL.Path)
+				}
+				var body map[string]interface{}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body) == 0 {
+					t.Errorf("Expected a body, got %v, %v", body, err)
+				}
+				if empty {
+					w.Write([]byte(`{"data": null}`))
+					return
+				}
+				w.Write([]byte(`{"data": {"_id": "w1"}}`))
+			}))
+			ctx := context.Background()
+
+			if id, err := tc.write(ctx, client); err != nil || id != "w1" {
+				t.Errorf("Expected w1, got %q, %v", id, err)
+			}
+			empty = true
+			if _, err := tc.write(ctx, client); err == nil || errors.Is(err, ErrNotFound) {
+				t.Errorf("Expected an error for a response without data, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package enbuild
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/internal/request"
+)
+
+// Response wraps the HTTP response of an API call with its status, headers,
+// pagination values, rate limit information and correlation ID
+type Response = request.Response
+
+// Rate represents the rate limit headers returned by the server
+type Rate = request.Rate
+
+// listResponse is the envelope returned by list endpoints
+type listResponse[T any] struct {
+	Data []T `json:"data"`
+	request.Pagination
+}
+
+// itemResponse is the envelope returned by single-resource endpoints
+type itemResponse struct {
+	Data json.RawMessage `json:"data"`
+}
+
+// doItem sends req and decodes the single resource in data, which the backend sends either as
+// an object or as an array. From an array, the first element accepted by match is returned, or the
+// only element when there is one. A missing or empty data yields ErrNotFound, and an array of several
+// elements none of which match yields an error wrapping ErrNotFound.
+func doItem[T any](ctx context.Context, client *request.Client, req *http.Request, match func(T) bool) (T, *Response, error) {
+	var zero T
+	var resp itemResponse
+	response, err := client.Do(ctx, req, &resp)
+	if err != nil {
+		return zero, response, err
+	}
+
+	data := bytes.TrimSpace(resp.Data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return zero, response, ErrNotFound
+	}
+
+	if data[0] != '[' {
+		var item T
+		if err := json.Unmarshal(data, &item); err != nil {
+			return zero, response, err
+		}
+		return item, response, nil
+	}
+
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return zero, response, err
+	}
+	if len(items) == 0 {
+		return zero, response, ErrNotFound
+	}
+	if match != nil {
+		for _, item := range items {
+			if match(item) {
+				return item, response, nil
+			}
+		}
+	}
+	if len(items) == 1 {
+		return items[0], response, nil
+	}
+	return zero, response, fmt.Errorf("none of the %d items in the response matches: %w", len(items), ErrNotFound)
+}
+
+// doWrite sends a request that creates or changes a resource and decodes the resource the server
+// returns. Unlike doItem, an empty data is reported as a malformed response rather than ErrNotFound.
+func doWrite[T any](ctx context.Context, client *request.Client, req *http.Request) (T, error) {
+	item, response, err := doItem[T](ctx, client, req, nil)
+	// doItem returns ErrNotFound itself, unwrapped, only for an empty data
+	if err == ErrNotFound && response != nil && response.StatusCode < 300 {
+		return item, fmt.Errorf("no data in response to %s %s", req.Method, req.URL.Path)
+	}
+	return item, err
+}
+
+// listPage lists the items at path, sending the query encoded from opts, and fills the pagination
+// values of the response
+func listPage[T any](ctx context.Context, client *request.Client, path string, opts interface{}) ([]T, *Response, error) {
+	path, err := request.AddQuery(path, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var resp listResponse[T]
+	response, err := client.Do(ctx, req, &resp)
+	if err != nil {
+		return nil, response, err
+	}
+	response.SetPagination(resp.Pagination, len(resp.Data))
+
+	return resp.Data, response, nil
+}
+
+// getByID gets the resource of the given kind at path, picking the one whose idOf is id when the
+// server answers with an array. It returns an error matching ErrNotFound when the resource does not exist.
+func getByID[E any](ctx context.Context, client *request.Client, kind, path, id string, idOf func(*E) ID) (*E, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%s ID is required", kind)
+	}
+
+	req, err := client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	item, _, err := doItem(ctx, client, req, func(e *E) bool {
+		return e != nil && idOf(e).String() == id
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting %s %s: %w", kind, id, err)
+	}
+	if item == nil {
+		return nil, fmt.Errorf("getting %s %s: %w", kind, id, ErrNotFound)
+	}
+	return item, nil
+}
+
+// writeItem sends body to path and decodes the resource of the given kind returned by the server
+func writeItem[E any](ctx context.Context, client *request.Client, kind, method, path string, body interface{}) (*E, error) {
+	req, err := client.NewRequest(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := doWrite[*E](ctx, client, req)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, fmt.Errorf("no %s in response to %s %s", kind, method, path)
+	}
+	return item, nil
+}
@@ -0,0 +1,41 @@
+package enbuild
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestListStacksWithResponse(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expectedPath := apiVersionPath + "stacks?page=1&limit=2"
+		if r.URL.String() != expectedPath {
+			t.Errorf("Expected path %s, got %s", expectedPath, r.URL.String())
+		}
+		w.Header().Set("X-Correlation-Id", "abc-123")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data":  []*Stack{{ID: "1", Name: "Stack1"}, {ID: "2", Name: "Stack2"}},
+			"total": 5,
+			"page":  1,
+			"limit": 2,
+		})
+	}))
+
+	stacks, resp, err := client.Stacks.ListStacksWithResponse(context.Background(), &StackListOptions{ListOptions: ListOptions{Page: 1, Limit: 2}})
+	if err != nil {
+		t.Fatalf("ListStacksWithResponse returned error: %v", err)
+	}
+	if len(stacks) != 2 {
+		t.Errorf("Expected 2 stacks, got %d", len(stacks))
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if resp.Total != 5 || resp.Page != 1 || resp.Limit != 2 || resp.NextPage != 2 {
+		t.Errorf("Unexpected pagination: total=%d page=%d limit=%d next=%d", resp.Total, resp.Page, resp.Limit, resp.NextPage)
+	}
+	if resp.CorrelationID != "abc-123" {
+		t.Errorf("Expected correlation ID abc-123, got %q", resp.CorrelationID)
+	}
+}
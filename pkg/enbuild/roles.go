@@ -0,0 +1,75 @@
+package enbuild
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/internal/request"
+)
+
+// RolesService administers roles and checks tokens
+type RolesService interface {
+	ListRoles(ctx context.Context) ([]*Role, error)
+	CreateRole(ctx context.Context, input *RoleInput) (*Role, error)
+	UpdateRole(ctx context.Context, id string, input *RoleInput) (*Role, error)
+	CheckAuth(ctx context.Context) (bool, error)
+}
+
+// rolesService implements RolesService on the user service
+type rolesService struct {
+	client *request.Client
+}
+
+var _ RolesService = (*rolesService)(nil)
+
+// ListRoles returns every role.
+func (s *rolesService) ListRoles(ctx context.Context) ([]*Role, error) {
+	roles, _, err := listPage[*Role](ctx, s.client, "roles", nil)
+	return roles, err
+}
+
+// CreateRole creates a role and returns it as stored by the server.
+func (s *rolesService) CreateRole(ctx context.Context, input *RoleInput) (*Role, error) {
+	if input == nil {
+		return nil, fmt.Errorf("role input is required")
+	}
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+	return writeItem[Role](ctx, s.client, "role", http.MethodPost, "roles", input)
+}
+
+// UpdateRole replaces the role with the given ID and returns it as stored by the server.
+func (s *rolesService) UpdateRole(ctx context.Context, id string, input *RoleInput) (*Role, error) {
+	if id == "" {
+		return nil, fmt.Errorf("role ID is required")
+	}
+	if input == nil {
+		return nil, fmt.Errorf("role input is required")
+	}
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+	return writeItem[Role](ctx, s.client, "role", http.MethodPut, fmt.Sprintf("roles/%s", id), input)
+}
+
+// CheckAuth reports whether the token of the client is valid. A rejected token is reported
+// as false with a nil error; other failures are returned as errors.
+func (s *rolesService) CheckAuth(ctx context.Context) (bool, error) {
+	req, err := s.client.NewRequest(ctx, http.MethodGet, "roles/auth", nil)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
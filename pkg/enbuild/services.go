@@ -0,0 +1,99 @@
+package enbuild
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/internal/request"
+)
+
+// Service identifies one of the ENBUILD microservices
+type Service string
+
+const (
+	// ServiceEnbuild serves catalogs, stacks, operations and repositories
+	ServiceEnbuild Service = "bk"
+	// ServiceUser serves users, roles and admin settings
+	ServiceUser Service = "user"
+	// ServiceML serves ML datasets
+	ServiceML Service = "ml"
+)
+
+// servicePaths are the paths the services are served under on the root URL by default
+var servicePaths = map[Service]string{
+	ServiceEnbuild: apiVersionPath,
+	ServiceUser:    userAPIPath,
+	ServiceML:      mlAPIPath,
+}
+
+// WithServiceURL routes a service to its own base URL, for installations serving
+// the microservices from different hosts, e.g. https://users.example.com/enbuild-user/api/v1/
+func WithServiceURL(service Service, baseURL string) ClientOption {
+	return func(ctx context.Context, c *Client) error {
+		if _, ok := servicePaths[service]; !ok {
+			return fmt.Errorf("unknown service %q", service)
+		}
+
+		parsedURL, err := url.Parse(baseURL)
+		if err != nil {
+			return fmt.Errorf("invalid %s service URL: %v", service, err)
+		}
+		if parsedURL.Scheme == "" || parsedURL.Host == "" {
+			return fmt.Errorf("invalid %s service URL %q: scheme and host are required", service, baseURL)
+		}
+		// Relative request paths resolve under the base URL only with a trailing slash
+		if !strings.HasSuffix(parsedURL.Path, "/") {
+			parsedURL.Path += "/"
+		}
+		c.serviceURLs[service] = parsedURL
+
+		if c.httpClient.Debug {
+			fmt.Printf("Using %s service URL: %s\n", service, parsedURL)
+		}
+
+		return nil
+	}
+}
+
+// ServiceURL returns the base URL requests to service are sent to
+func (c *Client) ServiceURL(service Service) string {
+	return c.serviceURL(service).String()
+}
+
+// serviceURL returns the override for service, or its default path under the root URL
+func (c *Client) serviceURL(service Service) *url.URL {
+	if u, ok := c.serviceURLs[service]; ok {
+		return u
+	}
+
+	u := *c.rootURL
+	u.Path = strings.TrimSuffix(u.Path, "/") + servicePaths[service]
+	return &u
+}
+
+// serviceClient returns a copy of the enbuild API client sending requests to service.
+// The copy shares the HTTP client, rate limiter, semaphore and circuit breaker.
+func (c *Client) serviceClient(service Service) *request.Client {
+	client := *c.httpClient
+	client.BaseURL = c.serviceURL(service)
+	return &client
+}
+
+// parseRootURL parses the root URL of an installation, dropping the enbuild API path
+// and anything after it for URLs given the way earlier versions expected them
+func parseRootURL(baseURL string) (*url.URL, error) {
+	parsedURL, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %v", err)
+	}
+
+	trimmed := strings.TrimSuffix(apiVersionPath, "/")
+	if i := strings.Index(parsedURL.Path+"/", trimmed+"/"); i >= 0 {
+		parsedURL.Path = parsedURL.Path[:i]
+	}
+	parsedURL.Path = strings.TrimSuffix(parsedURL.Path, "/")
+	parsedURL.RawPath = ""
+	return parsedURL, nil
+}
@@ -0,0 +1,113 @@
+package enbuild
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServiceURLs(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []ClientOption
+		expected map[Service]string
+	}{
+		{
+			name:    "DerivedFromRoot",
+			options: []ClientOption{WithBaseURL("https://enbuild.example.com")},
+			expected: map[Service]string{
+				ServiceEnbuild: "https://enbuild.example.com/enbuild-bk/api/v1/",
+				ServiceUser:    "https://enbuild.example.com/enbuild-user/api/v1/",
+				ServiceML:      "https://enbuild.example.com/enbuild-ml/api/v1/",
+			},
+		},
+		{
+			name:    "LegacyBaseURLWithAPIPath",
+			options: []ClientOption{WithBaseURL("https://enbuild.example.com/prefix/enbuild-bk/api/v1")},
+			expected: map[Service]string{
+				ServiceEnbuild: "https://enbuild.example.com/prefix/enbuild-bk/api/v1/",
+				ServiceUser:    "https://enbuild.example.com/prefix/enbuild-user/api/v1/",
+			},
+		},
+		{
+			name: "Override",
+			options: []ClientOption{
+				WithBaseURL("https://enbuild.example.com/"),
+				WithServiceURL(ServiceML, "https://ml.example.com/api"),
+			},
+			expected: map[Service]string{
+				ServiceEnbuild: "https://enbuild.example.com/enbuild-bk/api/v1/",
+				ServiceML:      "https://ml.example.com/api/",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withTestToken := func(ctx context.Context, c *Client) error {
+				c.httpClient.TokenProvider = func(context.Context) string { return "test-token" }
+				return nil
+			}
+			client, err := NewClient(context.Background(), append(tt.options, withTestToken)...)
+			if err != nil {
+				t.Fatalf("NewClient returned error: %v", err)
+			}
+			for service, expected := range tt.expected {
+				if got := client.ServiceURL(service); got != expected {
+					t.Errorf("Expected %s service URL %s, got %s", service, expected, got)
+				}
+			}
+		})
+	}
+
+	if _, err := NewClient(context.Background(), WithServiceURL("billing", "https://billing.example.com")); err == nil {
+		t.Error("Expected an error for an unknown service")
+	}
+}
+
+func TestSplitDeploymentSharesAuth(t *testing.T) {
+	// The user service runs on its own host and configures local authentication
+	userServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/api/adminSettings":
+			w.Write([]byte(`{"data": {"0": {"authMechanism": "local"}}}`))
+		case "/users/api/roles":
+			if r.Header.Get("Authorization") != "Bearer enbuild_local_admin_token" {
+				t.Errorf("Expected the local token, got %q", r.Header.Get("Authorization"))
+			}
+			w.Write([]byte(`{"data": [{"name": "admin"}]}`))
+		default:
+			t.Errorf("Unexpected user service request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(userServer.Close)
+
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != apiVersionPath+"stacks" {
+			t.Errorf("Unexpected enbuild service request %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer enbuild_local_admin_token" {
+			t.Errorf("Expected the local token, got %q", r.Header.Get("Authorization"))
+		}
+		w.Write([]byte(`{"data": []}`))
+	}))
+	t.Cleanup(apiServer.Close)
+
+	client, err := NewClient(context.Background(),
+		WithKeycloakAuth("admin", "secret"),
+		WithBaseURL(apiServer.URL),
+		WithServiceURL(ServiceUser, userServer.URL+"/users/api"),
+	)
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	if _, err := client.Roles.ListRoles(context.Background()); err != nil {
+		t.Errorf("ListRoles returned error: %v", err)
+	}
+	if _, err := client.Stacks.ListStacks(context.Background(), 1, 10, ""); err != nil {
+		t.Errorf("ListStacks returned error: %v", err)
+	}
+}
@@ -3,12 +3,163 @@ package enbuild
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
-	"net/url"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/internal/request"
 )
 
+// StacksService deploys and manages stacks
+type StacksService interface {
+	CreateStack(ctx context.Context, input *CreateStackInput) (*Stack, error)
+	GetStack(ctx context.Context, id string) (*Stack, error)
+	UpdateStack(ctx context.Context, id string, input *UpdateStackInput) (*Stack, error)
+	RedeployStack(ctx context.Context, id string) (*Stack, error)
+	RetryFailedStack(ctx context.Context, id string) (*Stack, error)
+	CloneStack(ctx context.Context, sourceID string, overrides *CloneStackOverrides) (*Stack, error)
+	DeleteStack(ctx context.Context, id string) error
+	DeleteMany(ctx context.Context, ids []string, opts BulkOptions) (BulkResults, error)
+	DeleteManyWhere(ctx context.Context, filter *StackFilter, opts BulkOptions) (BulkResults, error)
+	ListStacks(ctx context.Context, page int, limit int, searchTerm string) ([]*Stack, error)
+	ListStacksWithOptions(ctx context.Context, opts *StackListOptions) ([]*Stack, error)
+	ListStacksWithResponse(ctx context.Context, opts *StackListOptions) ([]*Stack, *Response, error)
+	AllStacks(ctx context.Context, opts *StackListOptions) iter.Seq2[*Stack, error]
+	ListAllStacks(ctx context.Context, opts *StackListOptions) ([]*Stack, error)
+	WaitForStatus(ctx context.Context, id string, opts *WaitOptions) (*Stack, error)
+	StreamLogs(ctx context.Context, id string, opts *LogStreamOptions) iter.Seq2[*LogEntry, error]
+}
+
+// stacksService implements StacksService on the enbuild API
+type stacksService struct {
+	client *request.Client
+	// catalogs fetches the catalogs stack inputs are validated against
+	catalogs CatalogsService
+}
+
+var _ StacksService = (*stacksService)(nil)
+
+// CreateStack deploys a new stack from a catalog and returns it with its ID and initial status.
+// Unless input.SkipValidation is set, the catalog is fetched first, by ID or else by slug, and the input validated against it,
+// returning a *ValidationError without creating anything when it does not fit.
+func (s *stacksService) CreateStack(ctx context.Context, input *CreateStackInput) (*Stack, error) {
+	if input == nil {
+		return nil, fmt.Errorf("stack input is required")
+	}
+
+	body := *input
+	if !input.SkipValidation {
+		catalog, err := s.stackCatalog(ctx, input.Catalog)
+		if err != nil {
+			return nil, err
+		}
+		if err := input.Validate(catalog); err != nil {
+			return nil, err
+		}
+
+		// Complete the catalog reference from the catalog itself
+		body.Catalog = catalog.Info()
+		if body.Type == "" {
+			body.Type = catalog.Type
+		}
+	}
+
+	return writeItem[Stack](ctx, s.client, "stack", http.MethodPost, "stacks", &body)
+}
+
+// GetStack returns a single stack by ID, including its logs, pipelines with their web URLs,
+// VCS project and permissions. It returns an error matching ErrNotFound when the stack does not exist.
+func (s *stacksService) GetStack(ctx context.Context, id string) (*Stack, error) {
+	return getByID(ctx, s.client, "stack", fmt.Sprintf("stacks/%s", id), id, func(s *Stack) ID { return s.ID })
+}
+
+// UpdateStack changes the component selection, infrastructure values or configuration of a stack
+// and returns the updated stack. Fields left empty in input are not changed. Unless
+// input.SkipValidation is set, the changes are first validated against the catalog of the stack,
+// returning a *ValidationError without updating anything when they do not fit.
+// Pass the stack ID to WaitForStatus to follow the resulting deployment.
+func (s *stacksService) UpdateStack(ctx context.Context, id string, input *UpdateStackInput) (*Stack, error) {
+	if id == "" {
+		return nil, fmt.Errorf("stack ID is required")
+	}
+	if input == nil {
+		return nil, fmt.Errorf("stack update input is required")
+	}
+
+	if !input.SkipValidation {
+		stack, err := s.GetStack(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		catalog, err := s.stackCatalog(ctx, stack.Catalog)
+		if err != nil {
+			return nil, err
+		}
+		if err := input.Validate(catalog); err != nil {
+			return nil, err
+		}
+	}
+
+	return writeItem[Stack](ctx, s.client, "stack", http.MethodPut, fmt.Sprintf("stacks/%s", id), input)
+}
+
+// stackCatalog fetches the catalog referenced by a stack, by ID or, when the reference has none, by slug
+func (s *stacksService) stackCatalog(ctx context.Context, ref CatalogInfo) (*Catalog, error) {
+	if ref.ID != "" {
+		return s.catalogs.GetCatalog(ctx, ref.ID.String(), nil)
+	}
+	if ref.Slug == "" {
+		return nil, &ValidationError{Problems: []string{"catalog ID or slug is required"}}
+	}
+
+	catalogs, err := s.catalogs.ListCatalog(ctx, &CatalogListOptions{Slug: ref.Slug})
+	if err != nil {
+		return nil, err
+	}
+	switch len(catalogs) {
+	case 0:
+		return nil, fmt.Errorf("getting catalog %s: %w", ref.Slug, ErrNotFound)
+	case 1:
+		return catalogs[0], nil
+	}
+	return nil, fmt.Errorf("%d catalogs have the slug %s", len(catalogs), ref.Slug)
+}
+
+// RedeployStack runs the pipeline of a stack again with its current inputs and returns the stack.
+// Pass the stack ID to WaitForStatus to follow the deployment.
+func (s *stacksService) RedeployStack(ctx context.Context, id string) (*Stack, error) {
+	if id == "" {
+		return nil, fmt.Errorf("stack ID is required")
+	}
+	return writeItem[Stack](ctx, s.client, "stack", http.MethodPost, fmt.Sprintf("stacks/%s/redeploy", id), nil)
+}
+
+// RetryFailedStack deploys again only the components of a stack whose logs are in a failure status
+// (see DefaultFailureStatuses) and returns the stack. It fails without calling the retry endpoint
+// when no component failed. Pass the stack ID to WaitForStatus to follow the deployment.
+func (s *stacksService) RetryFailedStack(ctx context.Context, id string) (*Stack, error) {
+	stack, err := s.GetStack(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var failed []string
+	for _, log := range stack.Logs {
+		if containsFold(DefaultFailureStatuses, log.Status) {
+			failed = append(failed, log.Slug)
+		}
+	}
+	if len(failed) == 0 {
+		return nil, fmt.Errorf("stack %s has no failed components to retry", id)
+	}
+
+	body := struct {
+		Components []string `json:"components"`
+	}{failed}
+	return writeItem[Stack](ctx, s.client, "stack", http.MethodPost, fmt.Sprintf("stacks/%s/retry", id), &body)
+}
+
 // DeleteStack deletes a stack by ID.
-func (s *Enbuild) DeleteStack(ctx context.Context, id string) error {
+func (s *stacksService) DeleteStack(ctx context.Context, id string) error {
 	path := fmt.Sprintf("stacks/%s", id)
 
 	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil)
@@ -19,21 +170,42 @@ func (s *Enbuild) DeleteStack(ctx context.Context, id string) error {
 	_, err = s.client.Do(ctx, req, nil)
 	return err
 }
+
+// ListStacks returns a list of stacks.
 // It accepts context, page, limit, and searchTerm for pagination and searching.
-func (s *Enbuild) ListStacks(ctx context.Context, page int, limit int, searchTerm string) ([]*Stack, error) {
-	encodedSearchTerm := url.QueryEscape(searchTerm)
-	path := fmt.Sprintf("stacks?page=%d&limit=%d&search=%s", page, limit, encodedSearchTerm)
+// Use ListStacksWithOptions to filter and sort the stacks.
+func (s *stacksService) ListStacks(ctx context.Context, page int, limit int, searchTerm string) ([]*Stack, error) {
+	stacks, _, err := listPage[*Stack](ctx, s.client, "stacks", &stackListParams{Page: page, Limit: limit, Search: searchTerm})
+	return stacks, err
+}
 
-	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
-	if err != nil {
-		return nil, err
-	}
+// stackListParams is the query sent by ListStacks, which always includes page, limit and search
+type stackListParams struct {
+	Page   int    `url:"page"`
+	Limit  int    `url:"limit"`
+	Search string `url:"search"`
+}
 
-	var resp struct {
-		Data []*Stack `json:"data"`
-	}
-	if _, err := s.client.Do(ctx, req, &resp); err != nil {
-		return nil, err
-	}
-	return resp.Data, nil
+// ListStacksWithOptions returns a page of stacks matching the filters in opts, in the requested order.
+func (s *stacksService) ListStacksWithOptions(ctx context.Context, opts *StackListOptions) ([]*Stack, error) {
+	stacks, _, err := s.ListStacksWithResponse(ctx, opts)
+	return stacks, err
+}
+
+// ListStacksWithResponse returns a list of stacks along with the response metadata,
+// which carries the pagination totals and the next page.
+func (s *stacksService) ListStacksWithResponse(ctx context.Context, opts *StackListOptions) ([]*Stack, *Response, error) {
+	return listPage[*Stack](ctx, s.client, "stacks", opts)
+}
+
+// AllStacks returns an iterator over the stacks of every page, fetching pages as the loop advances.
+// Breaking out of the loop stops fetching.
+// A zero opts.Limit uses the default page size, see ListOptions.
+func (s *stacksService) AllStacks(ctx context.Context, opts *StackListOptions) iter.Seq2[*Stack, error] {
+	return allItems(ctx, opts, s.ListStacksWithResponse)
+}
+
+// ListAllStacks returns the stacks of every page.
+func (s *stacksService) ListAllStacks(ctx context.Context, opts *StackListOptions) ([]*Stack, error) {
+	return listAllItems(ctx, opts, s.ListStacksWithResponse)
 }
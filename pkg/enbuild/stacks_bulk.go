@@ -0,0 +1,82 @@
+package enbuild
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// StackFilter selects stacks for the bulk stack methods
+type StackFilter struct {
+	// StackListOptions filters the stacks on the server. The filters are checked again on every
+	// listed stack, so that a server ignoring some of them cannot widen the selection.
+	StackListOptions
+
+	// OlderThan keeps the stacks created more than this long ago
+	OlderThan time.Duration
+	// Match, when set, keeps only the stacks it returns true for
+	Match func(stack *Stack) bool
+	// All must be set to select every stack with a filter that sets no condition
+	All bool
+}
+
+// hasFilters reports whether any condition is set
+func (f *StackFilter) hasFilters() bool {
+	return f.StackListOptions.hasFilters() || f.OlderThan > 0 || f.Match != nil
+}
+
+// DeleteMany deletes the stacks with the given IDs, at most opts.Concurrency at a time,
+// and returns the outcome of every ID along with the joined errors of the failed ones.
+func (s *stacksService) DeleteMany(ctx context.Context, ids []string, opts BulkOptions) (BulkResults, error) {
+	results := RunBulk(ctx, ids, opts, s.DeleteStack)
+	return results, results.Err()
+}
+
+// DeleteManyWhere deletes every stack selected by filter, e.g. the failed stacks of a bot user
+// older than a week. With opts.DryRun the results list the stacks that would be deleted.
+// A nil filter, or one that sets no condition without All, is rejected rather than deleting every stack.
+func (s *stacksService) DeleteManyWhere(ctx context.Context, filter *StackFilter, opts BulkOptions) (BulkResults, error) {
+	stacks, err := s.selectStacks(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(stacks))
+	for _, stack := range stacks {
+		ids = append(ids, stack.ID.String())
+	}
+	return s.DeleteMany(ctx, ids, opts)
+}
+
+// selectStacks lists every stack selected by filter
+func (s *stacksService) selectStacks(ctx context.Context, filter *StackFilter) ([]*Stack, error) {
+	if filter == nil {
+		return nil, errors.New("stack filter is required")
+	}
+	f := *filter
+	if !f.All && !f.hasFilters() {
+		return nil, errors.New("stack filter sets no condition, set All to select every stack")
+	}
+	options := f.StackListOptions
+	if f.OlderThan > 0 {
+		cutoff := time.Now().Add(-f.OlderThan)
+		if options.CreatedBefore.IsZero() || cutoff.Before(options.CreatedBefore) {
+			options.CreatedBefore = cutoff
+		}
+	}
+
+	var selected []*Stack
+	for stack, err := range s.AllStacks(ctx, &options) {
+		if err != nil {
+			return nil, err
+		}
+		if stack.ID == "" || !options.Matches(stack) {
+			continue
+		}
+		if f.Match != nil && !f.Match(stack) {
+			continue
+		}
+		selected = append(selected, stack)
+	}
+	return selected, nil
+}
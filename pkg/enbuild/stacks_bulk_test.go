@@ -0,0 +1,87 @@
+package enbuild_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+func TestDeleteManyWhere(t *testing.T) {
+	old := time.Now().AddDate(0, 0, -10).UTC().Format(time.RFC3339)
+	recent := time.Now().UTC().Format(time.RFC3339)
+
+	var mu sync.Mutex
+	var deleted []string
+	client := enbuild.NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			if r.URL.Query().Get("status") != "failed" || r.URL.Query().Get("createdBy") != "ci-bot" {
+				t.Errorf("Expected the filters to be sent, got %s", r.URL.RawQuery)
+			}
+			// The server ignores the filters, the client has to apply them again
+			w.Write([]byte(`{"data": [
+				{"_id": "s1", "status": "failed", "createdBy": "ci-bot", "createdOn": "` + old + `"},
+				{"_id": "s2", "status": "failed", "createdBy": "ci-bot", "createdOn": "` + recent + `"},
+				{"_id": "s3", "status": "success", "createdBy": "ci-bot", "createdOn": "` + old + `"},
+				{"_id": "s4", "status": "failed", "createdBy": "alice", "createdOn": "` + old + `"}
+			]}`))
+		case http.MethodDelete:
+			mu.Lock()
+			deleted = append(deleted, strings.TrimPrefix(r.URL.Path, expectedApiVersionPath+"stacks/"))
+			mu.Unlock()
+			w.Write([]byte(`{"data": {}}`))
+		}
+	}))
+	ctx := context.Background()
+	filter := &enbuild.StackFilter{
+		StackListOptions: enbuild.StackListOptions{Status: "failed", CreatedBy: "ci-bot"},
+		OlderThan:        7 * 24 * time.Hour,
+	}
+
+	results, err := client.Stacks.DeleteManyWhere(ctx, filter, enbuild.BulkOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("DeleteManyWhere returned error: %v", err)
+	}
+	if ids := results.IDs(enbuild.BulkDryRun); strings.Join(ids, ",") != "s1" || len(deleted) != 0 {
+		t.Errorf("Expected a dry run selecting s1, got %v and deletions %v", ids, deleted)
+	}
+
+	results, err = client.Stacks.DeleteManyWhere(ctx, filter, enbuild.BulkOptions{})
+	if err != nil {
+		t.Fatalf("DeleteManyWhere returned error: %v", err)
+	}
+	if strings.Join(results.IDs(enbuild.BulkSucceeded), ",") != "s1" || strings.Join(deleted, ",") != "s1" {
+		t.Errorf("Expected only s1 to be deleted, got %v", deleted)
+	}
+}
+
+func TestDeleteManyWhereRejectsEmptyFilter(t *testing.T) {
+	var requests int
+	client := enbuild.NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"data": [{"_id": "s1"}, {"_id": "s2"}]}`))
+	}))
+	ctx := context.Background()
+
+	for _, filter := range []*enbuild.StackFilter{nil, {}, {StackListOptions: enbuild.StackListOptions{Sort: enbuild.SortAsc("name")}}} {
+		if _, err := client.Stacks.DeleteManyWhere(ctx, filter, enbuild.BulkOptions{}); err == nil {
+			t.Errorf("Expected filter %+v to be rejected", filter)
+		}
+	}
+	if requests != 0 {
+		t.Errorf("Expected no request for a rejected filter, got %d", requests)
+	}
+
+	results, err := client.Stacks.DeleteManyWhere(ctx, &enbuild.StackFilter{All: true}, enbuild.BulkOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("DeleteManyWhere returned error: %v", err)
+	}
+	if ids := results.IDs(enbuild.BulkDryRun); strings.Join(ids, ",") != "s1,s2" {
+		t.Errorf("Expected All to select every stack, got %v", ids)
+	}
+}
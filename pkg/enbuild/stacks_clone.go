@@ -0,0 +1,167 @@
+package enbuild
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// secretFieldTypes are the configuration and infrastructure field types holding secrets
+var secretFieldTypes = []string{"password", "secret"}
+
+// CloneStackOverrides are the differences between a cloned stack and its source
+type CloneStackOverrides struct {
+	// Name of the new stack, required
+	Name string
+	// Configuration sets configuration variables, replacing the values of the source
+	Configuration map[string]string
+	// Infrastructure sets infrastructure field values, keyed by field variable or, failing that,
+	// by field key or name, replacing the values of the source
+	Infrastructure map[string]string
+	// Secrets sets the values of secret configuration variables and infrastructure fields.
+	// Secrets are never copied from the source, so every secret it had a value for must be given
+	// here or in Configuration or Infrastructure. Keys naming no secret of the stack are rejected.
+	Secrets map[string]string
+}
+
+// CloneStack creates a new stack from the catalog, components, infrastructure and configuration of
+// the source stack, with overrides applied. Secret values of the source are not copied: CloneStack
+// returns a *ValidationError naming every secret that is not re-supplied in overrides, and the new
+// stack is validated against the catalog like CreateStack does. Infrastructure fields are secret
+// unless the catalog marks them plaintext, or when their type is a secret type.
+func (s *stacksService) CloneStack(ctx context.Context, sourceID string, overrides *CloneStackOverrides) (*Stack, error) {
+	if overrides == nil {
+		return nil, &ValidationError{Problems: []string{"name is required"}}
+	}
+
+	source, err := s.GetStack(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	catalog, err := s.stackCatalog(ctx, source.Catalog)
+	if err != nil {
+		return nil, err
+	}
+
+	var v validator
+	input := cloneStackInput(&v, source, catalog, overrides)
+	var validationErr *ValidationError
+	if err := input.Validate(catalog); errors.As(err, &validationErr) {
+		v.problems = append(v.problems, validationErr.Problems...)
+	}
+	sort.Strings(v.problems)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	input.Catalog = catalog.Info()
+	if input.Type == "" {
+		input.Type = catalog.Type
+	}
+	input.SkipValidation = true
+	stack, err := s.CreateStack(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("cloning stack %s: %w", sourceID, err)
+	}
+	return stack, nil
+}
+
+// cloneStackInput builds the input creating a copy of source with overrides applied. It reports to v
+// the secrets the source had a value for that are not supplied by overrides, and the Secrets overrides
+// that do not name a secret of the stack.
+func cloneStackInput(v *validator, source *Stack, catalog *Catalog, overrides *CloneStackOverrides) *CreateStackInput {
+	input := &CreateStackInput{
+		Name:          overrides.Name,
+		Catalog:       source.Catalog,
+		Components:    append([]Component(nil), source.Components...),
+		Type:          source.Type,
+		Configuration: make(map[string]string),
+	}
+	var missing []string
+	secrets := make(map[string]bool)
+
+	for variable, value := range source.Configuration {
+		field, ok := catalog.ConfigField(variable)
+		if !ok || !isSecretField(field.Type) {
+			input.Configuration[variable] = value
+			continue
+		}
+		if value, ok := overrideValue(variable, overrides.Secrets, overrides.Configuration); ok {
+			input.Configuration[variable] = value
+		} else if value != "" {
+			missing = append(missing, variable)
+		}
+	}
+	for variable, value := range overrides.Configuration {
+		input.Configuration[variable] = value
+	}
+	for variable, value := range overrides.Secrets {
+		if field, ok := catalog.ConfigField(variable); ok && isSecretField(field.Type) {
+			input.Configuration[variable] = value
+			secrets[variable] = true
+		}
+	}
+
+	for _, selection := range source.Infrastructure {
+		selection.Fields = append([]InfraField(nil), selection.Fields...)
+		for i := range selection.Fields {
+			field := &selection.Fields[i]
+			key := field.fieldKey()
+			if isSecretInfraField(catalog, selection.Slug, *field) {
+				secrets[key] = true
+				value, ok := overrideValue(key, overrides.Secrets, overrides.Infrastructure)
+				if !ok && field.Value != "" && selection.Selected {
+					missing = append(missing, key)
+				}
+				field.Value = value
+				continue
+			}
+			if value, ok := overrides.Infrastructure[key]; ok {
+				field.Value = value
+			}
+		}
+		input.Infrastructure = append(input.Infrastructure, selection)
+	}
+
+	for _, secret := range missing {
+		v.addf("secret %q must be supplied, it is not copied from the source stack", secret)
+	}
+	for key := range overrides.Secrets {
+		if !secrets[key] {
+			v.addf("secret %q is not a secret configuration variable or infrastructure field of the stack", key)
+		}
+	}
+	return input
+}
+
+// isSecretInfraField reports whether a field of an infrastructure selection holds a secret. The field
+// definition of the catalog decides, failing that the field of the stack: a field is secret unless it is
+// marked plaintext, and always when its type is a secret type.
+func isSecretInfraField(catalog *Catalog, slug string, field InfraField) bool {
+	if selection, ok := catalog.InfraSelection(slug); ok {
+		for _, def := range selection.Fields {
+			if def.fieldKey() == field.fieldKey() {
+				field = def
+				break
+			}
+		}
+	}
+	return !field.Plaintext || isSecretField(field.Type)
+}
+
+// overrideValue returns the value of key in the first of the maps that has it
+func overrideValue(key string, maps ...map[string]string) (string, bool) {
+	for _, m := range maps {
+		if value, ok := m[key]; ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// isSecretField reports whether a field of the given type holds a secret
+func isSecretField(fieldType string) bool {
+	return containsFold(secretFieldTypes, strings.TrimSpace(fieldType))
+}
@@ -0,0 +1,97 @@
+package enbuild_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+func TestCloneStack(t *testing.T) {
+	var created enbuild.CreateStackInput
+	client := enbuild.NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == expectedApiVersionPath+"stacks/src":
+			w.Write([]byte(`{"data": {
+				"_id": "src",
+				"name": "dev",
+				"type": "terraform",
+				"catalog": {"id": "cat1", "slug": "eks"},
+				"components": [{"name": "cluster", "data": [{"id": 1, "name": "Cluster"}]}],
+				"infrastructure": [{"slug": "aws", "selected": true, "fields": [
+					{"name": "Access Key", "type": "text", "variable": "AWS_ACCESS_KEY_ID", "value": "AKIA1"},
+					{"name": "Secret Key", "type": "password", "variable": "AWS_SECRET_ACCESS_KEY", "value": "s3cr3t"},
+					{"name": "Session Token", "type": "text", "variable": "AWS_SESSION_TOKEN", "value": "t0ken"}
+				]}],
+				"configuration": {"region": "us-east-1", "admin_password": "hunter2"}
+			}}`))
+		case r.Method == http.MethodGet && r.URL.Path == expectedApiVersionPath+"manifests/cat1":
+			w.Write([]byte(`{"data": [` + enbuild.TestCatalogJSON + `]}`))
+		case r.Method == http.MethodPost && r.URL.Path == expectedApiVersionPath+"stacks":
+			if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+				t.Fatalf("Failed to decode body: %v", err)
+			}
+			w.Write([]byte(`{"data": {"_id": "clone", "name": "` + created.Name + `", "status": "pending"}}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	ctx := context.Background()
+
+	var validationErr *enbuild.ValidationError
+	_, err := client.Stacks.CloneStack(ctx, "src", &enbuild.CloneStackOverrides{Name: "staging"})
+	if !errors.As(err, &validationErr) || len(validationErr.Problems) != 3 {
+		t.Fatalf("Expected the three secrets to be reported missing, got %v", err)
+	}
+	for _, problem := range validationErr.Problems {
+		if strings.Contains(problem, "hunter2") || strings.Contains(problem, "s3cr3t") || strings.Contains(problem, "t0ken") {
+			t.Errorf("Secret value leaked into %q", problem)
+		}
+	}
+
+	stack, err := client.Stacks.CloneStack(ctx, "src", &enbuild.CloneStackOverrides{
+		Name:           "staging",
+		Configuration:  map[string]string{"region": "eu-west-1"},
+		Infrastructure: map[string]string{"AWS_ACCESS_KEY_ID": "AKIA2"},
+		Secrets: map[string]string{
+			"AWS_SECRET_ACCESS_KEY": "new-key",
+			"AWS_SESSION_TOKEN":     "new-token",
+			"admin_password":        "new-password",
+		},
+	})
+	if err != nil {
+		t.Fatalf("CloneStack returned error: %v", err)
+	}
+	if stack.ID != "clone" || created.Name != "staging" || created.Catalog.Slug != "eks" {
+		t.Errorf("Unexpected clone %+v from input %+v", stack, created)
+	}
+	if created.Configuration["region"] != "eu-west-1" || created.Configuration["admin_password"] != "new-password" {
+		t.Errorf("Unexpected configuration %v", created.Configuration)
+	}
+	fields := created.Infrastructure[0].Fields
+	if fields[0].Value != "AKIA2" || fields[1].Value != "new-key" || fields[2].Value != "new-token" {
+		t.Errorf("Unexpected infrastructure fields %+v", fields)
+	}
+	if len(created.Components) != 1 || created.Components[0].Name != "cluster" {
+		t.Errorf("Expected the components to be copied, got %+v", created.Components)
+	}
+
+	_, err = client.Stacks.CloneStack(ctx, "src", &enbuild.CloneStackOverrides{
+		Name: "staging",
+		Secrets: map[string]string{
+			"AWS_SECRET_ACCESS_KEY": "new-key",
+			"AWS_SESSION_TOKEN":     "new-token",
+			"admin_password":        "new-password",
+			"region":                "eu-west-1",
+		},
+	})
+	if !errors.As(err, &validationErr) || strings.Join(validationErr.Problems, "|") !=
+		`secret "region" is not a secret configuration variable or infrastructure field of the stack` {
+		t.Errorf("Expected the non-secret region to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package enbuild_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+func TestCreateStackInputValidate(t *testing.T) {
+	catalog := enbuild.DecodeTestCatalog(t)
+	aws, _ := catalog.InfraSelection("aws")
+
+	valid := func() *enbuild.CreateStackInput {
+		input := enbuild.NewCreateStackInput("dev", catalog)
+		input.SelectInfrastructure(*aws, map[string]string{"AWS_ACCESS_KEY_ID": "id", "AWS_SECRET_ACCESS_KEY": "secret"})
+		return input
+	}
+
+	testCases := []struct {
+		name     string
+		modify   func(in *enbuild.CreateStackInput)
+		problems []string
+	}{
+		{name: "Valid", modify: func(in *enbuild.CreateStackInput) {}},
+		{
+			name:     "MissingName",
+			modify:   func(in *enbuild.CreateStackInput) { in.Name = "" },
+			problems: []string{"name is required"},
+		},
+		{
+			name:     "MandatoryComponentMissing",
+			modify:   func(in *enbuild.CreateStackInput) { in.Components = nil },
+			problems: []string{`mandatory component "cluster" is not selected`},
+		},
+		{
+			name: "UnknownComponent",
+			modify: func(in *enbuild.CreateStackInput) {
+				in.Components = append(in.Components, enbuild.Component{Name: "logging"})
+			},
+			problems: []string{`component "logging" is not part of catalog eks`},
+		},
+		{
+			name:     "NoInfrastructure",
+			modify:   func(in *enbuild.CreateStackInput) { in.Infrastructure = nil },
+			problems: []string{"an infrastructure selection is required"},
+		},
+		{
+			name: "RequiredInfraFieldMissing",
+			modify: func(in *enbuild.CreateStackInput) {
+				in.SelectInfrastructure(*aws, map[string]string{"AWS_ACCESS_KEY_ID": "id"})
+			},
+			problems: []string{`infrastructure field "AWS_SECRET_ACCESS_KEY" is required`},
+		},
+		{
+			name:     "UnknownConfiguration",
+			modify:   func(in *enbuild.CreateStackInput) { in.Configuration["zone"] = "a" },
+			problems: []string{`configuration variable "zone" is not defined by catalog eks`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			input := valid()
+			tc.modify(input)
+
+			err := input.Validate(catalog)
+			if len(tc.problems) == 0 {
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				return
+			}
+
+			var validationErr *enbuild.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected a enbuild.ValidationError, got %v", err)
+			}
+			if strings.Join(validationErr.Problems, "|") != strings.Join(tc.problems, "|") {
+				t.Errorf("Expected problems %q, got %q", tc.problems, validationErr.Problems)
+			}
+		})
+	}
+}
+
+func TestCreateStack(t *testing.T) {
+	client := enbuild.NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == expectedApiVersionPath+"manifests/cat1":
+			w.Write([]byte(`{"data": [` + enbuild.TestCatalogJSON + `]}`))
+		case r.Method == http.MethodPost && r.URL.Path == expectedApiVersionPath+"stacks":
+			var body enbuild.CreateStackInput
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode body: %v", err)
+			}
+			if body.Catalog.Slug != "eks" || body.Type != "terraform" {
+				t.Errorf("Expected the catalog reference to be completed, got %+v", body.Catalog)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{"_id": "stack1", "name": body.Name, "status": "pending", "catalog": body.Catalog},
+			})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	ctx := context.Background()
+
+	input := &enbuild.CreateStackInput{
+		Name:           "dev",
+		Catalog:        enbuild.CatalogInfo{ID: "cat1"},
+		Components:     []enbuild.Component{{Name: "cluster"}},
+		Infrastructure: []enbuild.InfraSelect{{Slug: "azure", Selected: true}},
+	}
+	stack, err := client.Stacks.CreateStack(ctx, input)
+	if err != nil {
+		t.Fatalf("CreateStack returned error: %v", err)
+	}
+	if stack.ID != "stack1" || stack.Status != "pending" {
+		t.Errorf("Unexpected stack %+v", stack)
+	}
+
+	input.Components = nil
+	var validationErr *enbuild.ValidationError
+	if _, err := client.Stacks.CreateStack(ctx, input); !errors.As(err, &validationErr) {
+		t.Errorf("Expected a enbuild.ValidationError, got %v", err)
+	}
+}
+
+func TestCreateStackBySlug(t *testing.T) {
+	client := enbuild.NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == expectedApiVersionPath+"manifests":
+			w.Write([]byte(`{"data": [` + enbuild.TestCatalogJSON + `]}`))
+		case r.Method == http.MethodPost && r.URL.Path == expectedApiVersionPath+"stacks":
+			var body enbuild.CreateStackInput
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode body: %v", err)
+			}
+			if body.Catalog.ID != "cat1" {
+				t.Errorf("Expected the catalog ID to be resolved from the slug, got %+v", body.Catalog)
+			}
+			w.Write([]byte(`{"data": {"_id": "stack1", "status": "pending"}}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	ctx := context.Background()
+
+	input := &enbuild.CreateStackInput{
+		Name:           "dev",
+		Catalog:        enbuild.CatalogInfo{Slug: "eks"},
+		Components:     []enbuild.Component{{Name: "cluster"}},
+		Infrastructure: []enbuild.InfraSelect{{Slug: "azure", Selected: true}},
+	}
+	if stack, err := client.Stacks.CreateStack(ctx, input); err != nil || stack.ID != "stack1" {
+		t.Fatalf("CreateStack returned %+v, %v", stack, err)
+	}
+
+	input.Catalog = enbuild.CatalogInfo{Slug: "gke"}
+	if _, err := client.Stacks.CreateStack(ctx, input); !errors.Is(err, enbuild.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for an unknown slug, got %v", err)
+	}
+
+	input.Catalog = enbuild.CatalogInfo{}
+	var validationErr *enbuild.ValidationError
+	if _, err := client.Stacks.CreateStack(ctx, input); !errors.As(err, &validationErr) {
+		t.Errorf("Expected a enbuild.ValidationError without a catalog reference, got %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package enbuild
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+// fakeCatalogs serves a fixed catalog, standing in for the catalogs service
+type fakeCatalogs struct {
+	CatalogsService
+	catalog *Catalog
+}
+
+func (f *fakeCatalogs) GetCatalog(ctx context.Context, id string, opts *CatalogListOptions) (*Catalog, error) {
+	if id != f.catalog.ID.String() {
+		return nil, ErrNotFound
+	}
+	return f.catalog, nil
+}
+
+func TestCreateStackWithFakeCatalogs(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != apiVersionPath+"stacks" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"data": {"_id": "stack1", "status": "pending"}}`))
+	}))
+	stacks := client.Stacks.(*stacksService)
+	stacks.catalogs = &fakeCatalogs{catalog: DecodeTestCatalog(t)}
+
+	input := NewCreateStackInput("dev", DecodeTestCatalog(t))
+	input.Infrastructure = []InfraSelect{{Slug: "azure", Selected: true}}
+	if _, err := stacks.CreateStack(context.Background(), input); err != nil {
+		t.Errorf("CreateStack returned error: %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package enbuild_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+func TestGetStack(t *testing.T) {
+	client := enbuild.NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != expectedApiVersionPath+"stacks/stack1" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"statusCode": 404, "message": "Stack not found"}`))
+			return
+		}
+		w.Write([]byte(`{"data": {
+			"_id": "stack1",
+			"name": "dev",
+			"status": "running",
+			"project": {"id": 1234, "url": "https://gitlab.com/acme/dev"},
+			"pipeline": [
+				{"id": 1, "iid": 1, "status": "failed", "created_at": "2024-05-06T07:00:00Z", "web_url": "https://gitlab.com/acme/dev/-/pipelines/1"},
+				{"id": 2, "iid": 2, "status": "running", "created_at": "2024-05-06T08:00:00Z", "web_url": "https://gitlab.com/acme/dev/-/pipelines/2"}
+			],
+			"pending": 1,
+			"permissions": {"edit": true}
+		}}`))
+	}))
+	ctx := context.Background()
+
+	stack, err := client.Stacks.GetStack(ctx, "stack1")
+	if err != nil {
+		t.Fatalf("GetStack returned error: %v", err)
+	}
+	if stack.Project == nil || stack.Project.ID != "1234" || stack.Project.URL != "https://gitlab.com/acme/dev" {
+		t.Errorf("Unexpected project %+v", stack.Project)
+	}
+	if latest := stack.LatestPipeline(); latest == nil || latest.WebURL != "https://gitlab.com/acme/dev/-/pipelines/2" {
+		t.Errorf("Unexpected latest pipeline %+v", latest)
+	}
+	if stack.Pending != 1 || stack.Permissions["edit"] != true {
+		t.Errorf("Unexpected pending %d or permissions %v", stack.Pending, stack.Permissions)
+	}
+
+	if _, err := client.Stacks.GetStack(ctx, "missing"); !errors.Is(err, enbuild.ErrNotFound) {
+		t.Errorf("Expected enbuild.ErrNotFound, got %v", err)
+	}
+}
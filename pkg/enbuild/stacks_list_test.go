@@ -0,0 +1,46 @@
+package enbuild_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+func TestListStacksWithOptions(t *testing.T) {
+	var query string
+	client := enbuild.NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`{"data": [{"_id": "s1", "status": "failed"}]}`))
+	}))
+
+	stacks, err := client.Stacks.ListStacksWithOptions(context.Background(), &enbuild.StackListOptions{
+		ListOptions:  enbuild.ListOptions{Page: 1, Limit: 20},
+		Status:       "failed",
+		CatalogSlug:  "eks",
+		CreatedBy:    "ci-bot",
+		CreatedAfter: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		Sort:         enbuild.SortAsc("name"),
+	})
+	if err != nil {
+		t.Fatalf("ListStacksWithOptions returned error: %v", err)
+	}
+	if len(stacks) != 1 {
+		t.Errorf("Expected 1 stack, got %d", len(stacks))
+	}
+
+	expected := "page=1&limit=20&status=failed&catalog.slug=eks&createdBy=ci-bot&createdAfter=2024-05-01T00%3A00%3A00Z&sort=name"
+	if query != expected {
+		t.Errorf("Expected query %s, got %s", expected, query)
+	}
+
+	// Unset options are left out, as for every other list endpoint
+	if _, err := client.Stacks.ListStacksWithOptions(context.Background(), &enbuild.StackListOptions{Status: "failed"}); err != nil {
+		t.Fatalf("ListStacksWithOptions returned error: %v", err)
+	}
+	if query != "status=failed" {
+		t.Errorf("Expected query status=failed, got %s", query)
+	}
+}
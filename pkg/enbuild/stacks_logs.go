@@ -0,0 +1,118 @@
+package enbuild
+
+import (
+	"context"
+	"iter"
+	"time"
+)
+
+// defaultLogPollInterval is how often StreamLogs polls in follow mode by default
+const defaultLogPollInterval = 2 * time.Second
+
+// LogEntry is a single log line of a stack component
+type LogEntry struct {
+	// Slug and Name identify the component the line belongs to
+	Slug string
+	Name string
+	LogDetail
+}
+
+// LogStreamOptions configures StreamLogs
+type LogStreamOptions struct {
+	// Follow keeps polling for new entries until the stack reaches a terminal status
+	Follow bool
+	// Since skips entries logged at or before this time
+	Since time.Time
+	// Levels keeps only entries with one of these levels, ignoring case. Empty keeps every level.
+	Levels []string
+	// Components keeps only entries of the components with these slugs. Empty keeps every component.
+	Components []string
+	// PollInterval is the delay between polls in follow mode (default 2s)
+	PollInterval time.Duration
+
+	// SuccessStatuses and FailureStatuses end a follow, see WaitOptions for their defaults
+	SuccessStatuses []string
+	FailureStatuses []string
+
+	// OnDone, when set, is called with the final stack once a follow ends because the stack finished
+	OnDone func(stack *Stack)
+}
+
+// StreamLogs returns an iterator over the log entries of a stack. Without Follow it yields the
+// entries logged so far. With Follow it keeps polling and yields only the entries added since the
+// previous poll, per component, until the stack reaches a terminal status, then calls OnDone and stops.
+// Errors, including ctx cancellation, are yielded once and end the iteration.
+func (s *stacksService) StreamLogs(ctx context.Context, id string, opts *LogStreamOptions) iter.Seq2[*LogEntry, error] {
+	var options LogStreamOptions
+	if opts != nil {
+		options = *opts
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = defaultLogPollInterval
+	}
+	terminal := (&WaitOptions{SuccessStatuses: options.SuccessStatuses, FailureStatuses: options.FailureStatuses}).withDefaults()
+
+	return func(yield func(*LogEntry, error) bool) {
+		// seen counts the entries already read per component slug
+		seen := make(map[string]int)
+		for {
+			stack, err := s.GetStack(ctx, id)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for _, entry := range newLogEntries(stack, seen, &options) {
+				if !yield(entry, nil) {
+					return
+				}
+			}
+
+			if !options.Follow {
+				return
+			}
+			if terminal.isSuccess(stack.Status) || terminal.isFailure(stack.Status) {
+				if options.OnDone != nil {
+					options.OnDone(stack)
+				}
+				return
+			}
+
+			timer := time.NewTimer(options.PollInterval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				yield(nil, ctx.Err())
+				return
+			case <-timer.C:
+			}
+		}
+	}
+}
+
+// newLogEntries returns the entries of stack not counted in seen that pass the filters, and updates seen.
+// A component whose log got shorter is read again from the start, as happens when it is redeployed.
+func newLogEntries(stack *Stack, seen map[string]int, opts *LogStreamOptions) []*LogEntry {
+	var entries []*LogEntry
+	for _, log := range stack.Logs {
+		start := seen[log.Slug]
+		if start > len(log.Logs) {
+			start = 0
+		}
+		seen[log.Slug] = len(log.Logs)
+
+		if len(opts.Components) > 0 && !containsFold(opts.Components, log.Slug) {
+			continue
+		}
+		for _, detail := range log.Logs[start:] {
+			if !opts.Since.IsZero() && !detail.Timestamp.After(opts.Since) {
+				continue
+			}
+			if len(opts.Levels) > 0 && !containsFold(opts.Levels, detail.Level) {
+				continue
+			}
+			entries = append(entries, &LogEntry{Slug: log.Slug, Name: log.Name, LogDetail: detail})
+		}
+	}
+	return entries
+}
@@ -0,0 +1,83 @@
+package enbuild_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+func TestStreamLogs(t *testing.T) {
+	script := []string{
+		`{"_id": "s1", "status": "running", "logs": [
+			{"slug": "cluster", "logs": [{"timestamp": 1000, "msg": "init", "level": "info"}]}
+		]}`,
+		`{"_id": "s1", "status": "running", "logs": [
+			{"slug": "cluster", "logs": [{"timestamp": 1000, "msg": "init", "level": "info"}, {"timestamp": 2000, "msg": "plan", "level": "debug"}]},
+			{"slug": "monitoring", "logs": [{"timestamp": 2500, "msg": "queued", "level": "info"}]}
+		]}`,
+		`{"_id": "s1", "status": "success", "logs": [
+			{"slug": "cluster", "logs": [{"timestamp": 1000, "msg": "init", "level": "info"}, {"timestamp": 2000, "msg": "plan", "level": "debug"}, {"timestamp": 3000, "msg": "applied", "level": "info"}]},
+			{"slug": "monitoring", "logs": [{"timestamp": 2500, "msg": "queued", "level": "info"}, {"timestamp": 3500, "msg": "installed", "level": "INFO"}]}
+		]}`,
+	}
+
+	collect := func(t *testing.T, opts *enbuild.LogStreamOptions) ([]string, error) {
+		client := enbuild.NewTestClient(t, &scriptedStacks{script: script})
+		var msgs []string
+		for entry, err := range client.Stacks.StreamLogs(context.Background(), "s1", opts) {
+			if err != nil {
+				return msgs, err
+			}
+			msgs = append(msgs, entry.Slug+":"+entry.Msg)
+		}
+		return msgs, nil
+	}
+
+	t.Run("Snapshot", func(t *testing.T) {
+		msgs, err := collect(t, nil)
+		if err != nil {
+			t.Fatalf("StreamLogs yielded error: %v", err)
+		}
+		if strings.Join(msgs, ",") != "cluster:init" {
+			t.Errorf("Unexpected entries %v", msgs)
+		}
+	})
+
+	t.Run("FollowYieldsOnlyNewEntries", func(t *testing.T) {
+		var done *enbuild.Stack
+		msgs, err := collect(t, &enbuild.LogStreamOptions{
+			Follow:       true,
+			PollInterval: time.Millisecond,
+			OnDone:       func(stack *enbuild.Stack) { done = stack },
+		})
+		if err != nil {
+			t.Fatalf("StreamLogs yielded error: %v", err)
+		}
+		expected := "cluster:init,cluster:plan,monitoring:queued,cluster:applied,monitoring:installed"
+		if strings.Join(msgs, ",") != expected {
+			t.Errorf("Expected %s, got %v", expected, msgs)
+		}
+		if done == nil || done.Status != "success" {
+			t.Errorf("Expected OnDone with the finished stack, got %+v", done)
+		}
+	})
+
+	t.Run("FiltersBySinceAndLevel", func(t *testing.T) {
+		msgs, err := collect(t, &enbuild.LogStreamOptions{
+			Follow:       true,
+			PollInterval: time.Millisecond,
+			Since:        time.Unix(1000, 0),
+			Levels:       []string{"info"},
+		})
+		if err != nil {
+			t.Fatalf("StreamLogs yielded error: %v", err)
+		}
+		expected := "monitoring:queued,cluster:applied,monitoring:installed"
+		if strings.Join(msgs, ",") != expected {
+			t.Errorf("Expected %s, got %v", expected, msgs)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package enbuild_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+func TestUpdateStack(t *testing.T) {
+	var updated map[string]interface{}
+	client := enbuild.NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == expectedApiVersionPath+"stacks/stack1":
+			w.Write([]byte(`{"data": {"_id": "stack1", "name": "dev", "status": "success", "catalog": {"id": "cat1", "slug": "eks"}}}`))
+		case r.Method == http.MethodGet && r.URL.Path == expectedApiVersionPath+"manifests/cat1":
+			w.Write([]byte(`{"data": [` + enbuild.TestCatalogJSON + `]}`))
+		case r.Method == http.MethodPut && r.URL.Path == expectedApiVersionPath+"stacks/stack1":
+			if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+				t.Fatalf("Failed to decode body: %v", err)
+			}
+			w.Write([]byte(`{"data": {"_id": "stack1", "name": "dev", "status": "pending"}}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	ctx := context.Background()
+
+	stack, err := client.Stacks.UpdateStack(ctx, "stack1", &enbuild.UpdateStackInput{Configuration: map[string]string{"region": "eu-west-1"}})
+	if err != nil {
+		t.Fatalf("UpdateStack returned error: %v", err)
+	}
+	if stack.Status != "pending" {
+		t.Errorf("Unexpected stack %+v", stack)
+	}
+	if _, ok := updated["components"]; ok {
+		t.Errorf("Expected unchanged components to be left out, got %v", updated)
+	}
+
+	var validationErr *enbuild.ValidationError
+	_, err = client.Stacks.UpdateStack(ctx, "stack1", &enbuild.UpdateStackInput{Configuration: map[string]string{"unknown": "x"}})
+	if !errors.As(err, &validationErr) {
+		t.Errorf("Expected a enbuild.ValidationError, got %v", err)
+	}
+}
+
+func TestRedeployAndRetryStack(t *testing.T) {
+	var retried []string
+	client := enbuild.NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == expectedApiVersionPath+"stacks/stack1":
+			w.Write([]byte(`{"data": {"_id": "stack1", "status": "failed", "logs": [
+				{"slug": "cluster", "status": "success"},
+				{"slug": "monitoring", "status": "failed"}
+			]}}`))
+		case r.Method == http.MethodGet && r.URL.Path == expectedApiVersionPath+"stacks/stack2":
+			w.Write([]byte(`{"data": {"_id": "stack2", "status": "success", "logs": [{"slug": "cluster", "status": "success"}]}}`))
+		case r.Method == http.MethodPost && r.URL.Path == expectedApiVersionPath+"stacks/stack1/redeploy":
+			w.Write([]byte(`{"data": {"_id": "stack1", "status": "pending"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == expectedApiVersionPath+"stacks/stack1/retry":
+			var body struct {
+				Components []string `json:"components"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode body: %v", err)
+			}
+			retried = body.Components
+			w.Write([]byte(`{"data": {"_id": "stack1", "status": "pending"}}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	ctx := context.Background()
+
+	if stack, err := client.Stacks.RedeployStack(ctx, "stack1"); err != nil || stack.Status != "pending" {
+		t.Errorf("RedeployStack returned %+v, %v", stack, err)
+	}
+
+	if stack, err := client.Stacks.RetryFailedStack(ctx, "stack1"); err != nil || stack.Status != "pending" {
+		t.Errorf("RetryFailedStack returned %+v, %v", stack, err)
+	}
+	if strings.Join(retried, ",") != "monitoring" {
+		t.Errorf("Expected only the failed component to be retried, got %v", retried)
+	}
+
+	if _, err := client.Stacks.RetryFailedStack(ctx, "stack2"); err == nil {
+		t.Error("Expected an error retrying a stack without failed components")
+	}
+}
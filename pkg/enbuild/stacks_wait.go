@@ -0,0 +1,179 @@
+package enbuild
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPollInterval    = 5 * time.Second
+	defaultMaxPollInterval = time.Minute
+	defaultPollMultiplier  = 1.5
+)
+
+var (
+	// DefaultSuccessStatuses are the stack statuses WaitForStatus treats as success by default
+	DefaultSuccessStatuses = []string{"success", "succeeded", "completed", "deployed"}
+	// DefaultFailureStatuses are the stack statuses WaitForStatus treats as failure by default
+	DefaultFailureStatuses = []string{"failed", "error", "canceled", "cancelled"}
+)
+
+// ErrStackFailed is matched by errors.Is when a stack ends in a failure status.
+// Use errors.As with *StackFailedError to get the stack and its failing logs.
+var ErrStackFailed = errors.New("stack failed")
+
+// StackFailedError is returned when a stack reaches a failure status
+type StackFailedError struct {
+	Stack  *Stack
+	Status string
+	// FailedLogs are the component logs in a failure status, or every log when none is
+	FailedLogs []StackLog
+}
+
+// Error names the stack, its status and the failing components
+func (e *StackFailedError) Error() string {
+	var components []string
+	for _, log := range e.FailedLogs {
+		components = append(components, log.Slug)
+	}
+	msg := fmt.Sprintf("stack %s ended with status %q", e.Stack.ID, e.Status)
+	if len(components) > 0 {
+		msg += fmt.Sprintf(" (components: %s)", strings.Join(components, ", "))
+	}
+	return msg
+}
+
+// Is lets errors.Is match ErrStackFailed
+func (e *StackFailedError) Is(target error) bool {
+	return target == ErrStackFailed
+}
+
+// WaitOptions configures WaitForStatus. Zero values fall back to the defaults noted on each field.
+type WaitOptions struct {
+	// PollInterval is the delay after the first poll and after every change (default 5s)
+	PollInterval time.Duration
+	// MaxInterval caps the delay between polls while nothing changes (default 1m)
+	MaxInterval time.Duration
+	// Multiplier grows the delay after every poll that saw no change (default 1.5)
+	Multiplier float64
+
+	// SuccessStatuses end the wait successfully (default DefaultSuccessStatuses)
+	SuccessStatuses []string
+	// FailureStatuses end the wait with a *StackFailedError (default DefaultFailureStatuses)
+	FailureStatuses []string
+
+	// OnProgress, when set, is called with the stack whenever its status or logs change
+	OnProgress func(stack *Stack)
+}
+
+// withDefaults returns a copy of o with defaults applied
+func (o *WaitOptions) withDefaults() WaitOptions {
+	var opts WaitOptions
+	if o != nil {
+		opts = *o
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultPollInterval
+	}
+	if opts.MaxInterval < opts.PollInterval {
+		opts.MaxInterval = max(defaultMaxPollInterval, opts.PollInterval)
+	}
+	if opts.Multiplier < 1 {
+		opts.Multiplier = defaultPollMultiplier
+	}
+	if len(opts.SuccessStatuses) == 0 {
+		opts.SuccessStatuses = DefaultSuccessStatuses
+	}
+	if len(opts.FailureStatuses) == 0 {
+		opts.FailureStatuses = DefaultFailureStatuses
+	}
+	return opts
+}
+
+// isSuccess reports whether status is one of the success statuses
+func (o *WaitOptions) isSuccess(status string) bool {
+	return containsFold(o.SuccessStatuses, status)
+}
+
+// isFailure reports whether status is one of the failure statuses
+func (o *WaitOptions) isFailure(status string) bool {
+	return containsFold(o.FailureStatuses, status)
+}
+
+// WaitForStatus polls the stack until it reaches a success or failure status, backing off while
+// nothing changes. It returns the final stack on success, and a *StackFailedError matching
+// ErrStackFailed on failure. Cancel ctx to stop waiting.
+func (s *stacksService) WaitForStatus(ctx context.Context, id string, opts *WaitOptions) (*Stack, error) {
+	options := opts.withDefaults()
+
+	interval := options.PollInterval
+	var lastState string
+	for first := true; ; first = false {
+		stack, err := s.GetStack(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if state := stackProgress(stack); first || state != lastState {
+			lastState = state
+			interval = options.PollInterval
+			if options.OnProgress != nil {
+				options.OnProgress(stack)
+			}
+		} else {
+			interval = min(time.Duration(float64(interval)*options.Multiplier), options.MaxInterval)
+		}
+
+		switch {
+		case options.isSuccess(stack.Status):
+			return stack, nil
+		case options.isFailure(stack.Status):
+			return stack, &StackFailedError{Stack: stack, Status: stack.Status, FailedLogs: failedLogs(stack, &options)}
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return stack, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// stackProgress summarizes the status and logs of a stack, so that any change shows up as a different value
+func stackProgress(stack *Stack) string {
+	var b strings.Builder
+	b.WriteString(stack.Status)
+	for _, log := range stack.Logs {
+		fmt.Fprintf(&b, "|%s:%s:%d", log.Slug, log.Status, len(log.Logs))
+	}
+	return b.String()
+}
+
+// failedLogs returns the component logs in a failure status, or every log when none is
+func failedLogs(stack *Stack, opts *WaitOptions) []StackLog {
+	var failed []StackLog
+	for _, log := range stack.Logs {
+		if opts.isFailure(log.Status) {
+			failed = append(failed, log)
+		}
+	}
+	if len(failed) == 0 {
+		return stack.Logs
+	}
+	return failed
+}
+
+// containsFold reports whether values contains s, ignoring case
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package enbuild_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+// scriptedStacks serves the stacks in script one GET at a time, repeating the last one
+type scriptedStacks struct {
+	script []string
+	polls  int
+}
+
+func (s *scriptedStacks) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	step := s.script[min(s.polls, len(s.script)-1)]
+	s.polls++
+	w.Write([]byte(`{"data": ` + step + `}`))
+}
+
+func TestWaitForStatus(t *testing.T) {
+	fast := &enbuild.WaitOptions{PollInterval: time.Millisecond, MaxInterval: 2 * time.Millisecond}
+
+	t.Run("Success", func(t *testing.T) {
+		server := &scriptedStacks{script: []string{
+			`{"_id": "s1", "status": "pending"}`,
+			`{"_id": "s1", "status": "pending"}`,
+			`{"_id": "s1", "status": "running", "logs": [{"slug": "cluster", "status": "running", "logs": [{"msg": "apply"}]}]}`,
+			`{"_id": "s1", "status": "running", "logs": [{"slug": "cluster", "status": "running", "logs": [{"msg": "apply"}, {"msg": "done"}]}]}`,
+			`{"_id": "s1", "status": "Success"}`,
+		}}
+		client := enbuild.NewTestClient(t, server)
+
+		var seen []string
+		options := *fast
+		options.OnProgress = func(stack *enbuild.Stack) { seen = append(seen, stack.Status) }
+
+		stack, err := client.Stacks.WaitForStatus(context.Background(), "s1", &options)
+		if err != nil {
+			t.Fatalf("WaitForStatus returned error: %v", err)
+		}
+		if stack.Status != "Success" || server.polls != 5 {
+			t.Errorf("Expected to stop at Success after 5 polls, got %s after %d", stack.Status, server.polls)
+		}
+		if strings.Join(seen, ",") != "pending,running,running,Success" {
+			t.Errorf("Unexpected progress callbacks %v", seen)
+		}
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		client := enbuild.NewTestClient(t, &scriptedStacks{script: []string{
+			`{"_id": "s1", "status": "running"}`,
+			`{"_id": "s1", "status": "failed", "logs": [
+				{"slug": "cluster", "status": "success"},
+				{"slug": "monitoring", "status": "failed", "logs": [{"msg": "helm timeout", "level": "error"}]}
+			]}`,
+		}})
+
+		_, err := client.Stacks.WaitForStatus(context.Background(), "s1", fast)
+		if !errors.Is(err, enbuild.ErrStackFailed) {
+			t.Fatalf("Expected enbuild.ErrStackFailed, got %v", err)
+		}
+		var failed *enbuild.StackFailedError
+		if !errors.As(err, &failed) || len(failed.FailedLogs) != 1 || failed.FailedLogs[0].Slug != "monitoring" {
+			t.Errorf("Expected the monitoring log only, got %+v", failed)
+		}
+	})
+
+	t.Run("CustomTerminalStatuses", func(t *testing.T) {
+		client := enbuild.NewTestClient(t, &scriptedStacks{script: []string{`{"_id": "s1", "status": "awaiting-approval"}`}})
+
+		options := *fast
+		options.SuccessStatuses = []string{"awaiting-approval"}
+		if _, err := client.Stacks.WaitForStatus(context.Background(), "s1", &options); err != nil {
+			t.Errorf("Expected a custom success status to end the wait, got %v", err)
+		}
+	})
+
+	t.Run("Cancellation", func(t *testing.T) {
+		client := enbuild.NewTestClient(t, &scriptedStacks{script: []string{`{"_id": "s1", "status": "running"}`}})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if _, err := client.Stacks.WaitForStatus(ctx, "s1", fast); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,153 @@
+package enbuild
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ID identifies an ENBUILD resource. The backend uses Mongo ObjectID strings for most documents
+// and numbers for some embedded ones; both decode into an ID, as does the extended JSON form {"$oid": "..."}.
+// An ID always encodes as a JSON string, so a string ID such as "7" is sent back unchanged.
+type ID string
+
+// String returns the ID as a string
+func (id ID) String() string {
+	return string(id)
+}
+
+// UnmarshalJSON accepts strings, numbers, {"$oid": "..."} and null
+func (id *ID) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		*id = ""
+		return nil
+	case len(data) > 0 && data[0] == '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*id = ID(s)
+		return nil
+	case len(data) > 0 && data[0] == '{':
+		var oid struct {
+			OID string `json:"$oid"`
+		}
+		if err := json.Unmarshal(data, &oid); err != nil {
+			return err
+		}
+		*id = ID(oid.OID)
+		return nil
+	default:
+		var n json.Number
+		if err := json.Unmarshal(data, &n); err != nil {
+			return fmt.Errorf("invalid ID %s: %w", data, err)
+		}
+		// Floats such as 1.0 come from JavaScript clients, keep the integer form
+		if f, err := n.Float64(); err == nil && f == float64(int64(f)) {
+			*id = ID(strconv.FormatInt(int64(f), 10))
+			return nil
+		}
+		*id = ID(n.String())
+		return nil
+	}
+}
+
+// MarshalJSON encodes the ID as a JSON string
+func (id ID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(id))
+}
+
+// Timestamp is a point in time as sent by the backend: an ISO 8601 string,
+// or seconds or milliseconds since the epoch as a number or numeric string.
+// The zero Timestamp encodes as null.
+type Timestamp struct {
+	time.Time
+}
+
+// timestampLayouts are the string formats accepted by Timestamp, tried in order
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// epochMillisThreshold separates epoch seconds from epoch milliseconds.
+// 1e11 seconds is in the year 5138, while 1e11 milliseconds is in 1973.
+const epochMillisThreshold = 1e11
+
+// UnmarshalJSON accepts ISO 8601 strings, epoch seconds or milliseconds, {"$date": ...} and null
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '{' {
+		var date struct {
+			Date json.RawMessage `json:"$date"`
+		}
+		if err := json.Unmarshal(data, &date); err != nil {
+			return err
+		}
+		return t.UnmarshalJSON(date.Date)
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		parsed, err := ParseTimestamp(s)
+		if err != nil {
+			return err
+		}
+		*t = parsed
+		return nil
+	}
+
+	parsed, err := ParseTimestamp(string(data))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// MarshalJSON encodes the timestamp as an RFC 3339 string, or null when zero
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.UTC().Format(time.RFC3339Nano))
+}
+
+// ParseTimestamp parses the timestamp formats used by the backend. An empty string yields the zero Timestamp.
+func ParseTimestamp(s string) (Timestamp, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Timestamp{}, nil
+	}
+
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		if f >= epochMillisThreshold || f <= -epochMillisThreshold {
+			return Timestamp{time.UnixMilli(int64(f)).UTC()}, nil
+		}
+		sec := int64(f)
+		return Timestamp{time.Unix(sec, int64((f-float64(sec))*1e9)).UTC()}, nil
+	}
+
+	for _, layout := range timestampLayouts {
+		if parsed, err := time.Parse(layout, s); err == nil {
+			return Timestamp{parsed}, nil
+		}
+	}
+	return Timestamp{}, fmt.Errorf("invalid timestamp %q", s)
+}
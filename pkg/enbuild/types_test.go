@@ -0,0 +1,154 @@
+package enbuild_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+func TestIDUnmarshalJSON(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected enbuild.ID
+	}{
+		{name: "ObjectIDString", input: `"6638a128d6852d0012a27491"`, expected: "6638a128d6852d0012a27491"},
+		{name: "Integer", input: `42`, expected: "42"},
+		{name: "WholeFloat", input: `42.0`, expected: "42"},
+		{name: "LargeInteger", input: `1715000000000`, expected: "1715000000000"},
+		{name: "ExtendedJSON", input: `{"$oid": "6638a128d6852d0012a27491"}`, expected: "6638a128d6852d0012a27491"},
+		{name: "Null", input: `null`, expected: ""},
+		{name: "NumericString", input: `"7"`, expected: "7"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var id enbuild.ID
+			if err := json.Unmarshal([]byte(tc.input), &id); err != nil {
+				t.Fatalf("Unmarshal returned error: %v", err)
+			}
+			if id != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, id)
+			}
+		})
+	}
+
+	var id enbuild.ID
+	if err := json.Unmarshal([]byte(`true`), &id); err == nil {
+		t.Errorf("Expected an error for a boolean ID")
+	}
+}
+
+func TestIDMarshalJSON(t *testing.T) {
+	testCases := []struct {
+		id       enbuild.ID
+		expected string
+	}{
+		{id: "6638a128d6852d0012a27491", expected: `"6638a128d6852d0012a27491"`},
+		{id: "42", expected: `"42"`},
+		{id: "-3", expected: `"-3"`},
+		{id: "007", expected: `"007"`},
+		{id: "+5", expected: `"+5"`},
+		{id: "stack-1", expected: `"stack-1"`},
+		{id: "", expected: `""`},
+	}
+
+	for _, tc := range testCases {
+		data, err := json.Marshal(tc.id)
+		if err != nil {
+			t.Fatalf("Marshal returned error: %v", err)
+		}
+		if string(data) != tc.expected {
+			t.Errorf("Expected %s, got %s", tc.expected, data)
+		}
+	}
+}
+
+func TestIDRoundTrip(t *testing.T) {
+	for _, input := range []string{`"7"`, `"007"`, `"+5"`, `"stack-1"`, `"6638a128d6852d0012a27491"`} {
+		var id enbuild.ID
+		if err := json.Unmarshal([]byte(input), &id); err != nil {
+			t.Fatalf("Unmarshal %s returned error: %v", input, err)
+		}
+		data, err := json.Marshal(struct {
+			ID enbuild.ID `json:"id"`
+		}{id})
+		if err != nil {
+			t.Fatalf("Marshal %q returned error: %v", id, err)
+		}
+		if expected := `{"id":` + input + `}`; string(data) != expected {
+			t.Errorf("Expected %s, got %s", expected, data)
+		}
+	}
+}
+
+func TestTimestampUnmarshalJSON(t *testing.T) {
+	expected := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected time.Time
+	}{
+		{name: "ISOWithMillis", input: `"2024-05-06T07:08:09.000Z"`, expected: expected},
+		{name: "RFC3339Offset", input: `"2024-05-06T09:08:09+02:00"`, expected: expected},
+		{name: "WithoutZone", input: `"2024-05-06T07:08:09"`, expected: expected},
+		{name: "SpaceSeparated", input: `"2024-05-06 07:08:09"`, expected: expected},
+		{name: "EpochSeconds", input: `1714979289`, expected: expected},
+		{name: "EpochMillis", input: `1714979289000`, expected: expected},
+		{name: "EpochMillisString", input: `"1714979289000"`, expected: expected},
+		{name: "ExtendedJSON", input: `{"$date": "2024-05-06T07:08:09Z"}`, expected: expected},
+		{name: "DateOnly", input: `"2024-05-06"`, expected: time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)},
+		{name: "Null", input: `null`},
+		{name: "Empty", input: `""`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var ts enbuild.Timestamp
+			if err := json.Unmarshal([]byte(tc.input), &ts); err != nil {
+				t.Fatalf("Unmarshal returned error: %v", err)
+			}
+			if !ts.Equal(tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, ts.Time)
+			}
+		})
+	}
+
+	var ts enbuild.Timestamp
+	if err := json.Unmarshal([]byte(`"yesterday"`), &ts); err == nil {
+		t.Errorf("Expected an error for an invalid timestamp")
+	}
+}
+
+func TestStackDecodesIDsAndTimestamps(t *testing.T) {
+	input := `{
+		"_id": "6638a128d6852d0012a27491",
+		"name": "dev",
+		"catalog": {"id": 17, "slug": "eks"},
+		"created_on": "2024-05-06T07:08:09Z",
+		"updatedOn": 1714979289000,
+		"logs": [{"slug": "cluster", "logs": [{"timestamp": 1714979289000, "msg": "started"}]}]
+	}`
+
+	var stack enbuild.Stack
+	if err := json.Unmarshal([]byte(input), &stack); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+
+	expected := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	if stack.ID != "6638a128d6852d0012a27491" || stack.Catalog.ID != "17" {
+		t.Errorf("Unexpected IDs: stack %q, catalog %q", stack.ID, stack.Catalog.ID)
+	}
+	if !stack.CreatedOn.Equal(expected) {
+		t.Errorf("Expected CreatedOn from created_on, got %v", stack.CreatedOn)
+	}
+	if !stack.UpdatedOn.Equal(expected) {
+		t.Errorf("Expected UpdatedOn %v, got %v", expected, stack.UpdatedOn)
+	}
+	if !stack.Logs[0].Logs[0].Timestamp.Equal(expected) {
+		t.Errorf("Expected log timestamp %v, got %v", expected, stack.Logs[0].Logs[0].Timestamp)
+	}
+}
@@ -0,0 +1,83 @@
+package enbuild
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/internal/request"
+)
+
+// UsersService administers user accounts
+type UsersService interface {
+	ListUsers(ctx context.Context, opts *UserListOptions) ([]*User, error)
+	ListUsersWithResponse(ctx context.Context, opts *UserListOptions) ([]*User, *Response, error)
+	GetUser(ctx context.Context, id string) (*User, error)
+	CreateUser(ctx context.Context, input *UserInput) (*User, error)
+	UpdateUser(ctx context.Context, id string, input *UserInput) (*User, error)
+	AllUsers(ctx context.Context, opts *UserListOptions) iter.Seq2[*User, error]
+	ListAllUsers(ctx context.Context, opts *UserListOptions) ([]*User, error)
+}
+
+// usersService implements UsersService on the user service
+type usersService struct {
+	client *request.Client
+}
+
+var _ UsersService = (*usersService)(nil)
+
+// ListUsers returns a page of users matching the filters in opts.
+func (s *usersService) ListUsers(ctx context.Context, opts *UserListOptions) ([]*User, error) {
+	users, _, err := s.ListUsersWithResponse(ctx, opts)
+	return users, err
+}
+
+// ListUsersWithResponse returns a page of users along with the response metadata,
+// which carries the pagination totals and the next page.
+func (s *usersService) ListUsersWithResponse(ctx context.Context, opts *UserListOptions) ([]*User, *Response, error) {
+	return listPage[*User](ctx, s.client, "users", opts)
+}
+
+// GetUser returns a single user by ID.
+// It returns an error matching ErrNotFound when the user does not exist.
+func (s *usersService) GetUser(ctx context.Context, id string) (*User, error) {
+	return getByID(ctx, s.client, "user", fmt.Sprintf("users/%s", id), id, func(u *User) ID { return u.ID })
+}
+
+// CreateUser creates a user, e.g. with Roles set to []string{RoleDevOps}, and returns it as stored by the server.
+func (s *usersService) CreateUser(ctx context.Context, input *UserInput) (*User, error) {
+	if input == nil {
+		return nil, fmt.Errorf("user input is required")
+	}
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+	return writeItem[User](ctx, s.client, "user", http.MethodPost, "users", input)
+}
+
+// UpdateUser replaces the user with the given ID and returns it as stored by the server.
+// Use it to change the roles of a user.
+func (s *usersService) UpdateUser(ctx context.Context, id string, input *UserInput) (*User, error) {
+	if id == "" {
+		return nil, fmt.Errorf("user ID is required")
+	}
+	if input == nil {
+		return nil, fmt.Errorf("user input is required")
+	}
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+	return writeItem[User](ctx, s.client, "user", http.MethodPut, fmt.Sprintf("users/%s", id), input)
+}
+
+// AllUsers returns an iterator over the users of every page.
+// A zero opts.Limit uses the default page size, see ListOptions.
+func (s *usersService) AllUsers(ctx context.Context, opts *UserListOptions) iter.Seq2[*User, error] {
+	return allItems(ctx, opts, s.ListUsersWithResponse)
+}
+
+// ListAllUsers returns the users of every page.
+func (s *usersService) ListAllUsers(ctx context.Context, opts *UserListOptions) ([]*User, error) {
+	return listAllItems(ctx, opts, s.ListUsersWithResponse)
+}
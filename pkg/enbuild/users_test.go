@@ -0,0 +1,51 @@
+package enbuild
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestUsersAndRoles(t *testing.T) {
+	validToken := true
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			t.Errorf("Expected the shared token, got %q", r.Header.Get("Authorization"))
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == userAPIPath+"users":
+			w.Write([]byte(`{"data": [{"_id": "u1", "email": "dev@acme.io", "roles": ["devops"]}]}`))
+		case r.Method == http.MethodGet && r.URL.Path == userAPIPath+"roles":
+			w.Write([]byte(`{"data": [{"_id": "r1", "name": "admin"}, {"_id": "r2", "name": "devops"}]}`))
+		case r.Method == http.MethodGet && r.URL.Path == userAPIPath+"roles/auth":
+			if !validToken {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"statusCode": 401, "message": "Unauthorized"}`))
+				return
+			}
+			w.Write([]byte(`{"data": true}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	ctx := context.Background()
+
+	users, err := client.Users.ListUsers(ctx, nil)
+	if err != nil || len(users) != 1 || !users[0].HasRole(RoleDevOps) || users[0].HasRole(RoleAdmin) {
+		t.Fatalf("ListUsers returned %+v, %v", users, err)
+	}
+
+	roles, err := client.Roles.ListRoles(ctx)
+	if err != nil || len(roles) != 2 || roles[0].Name != RoleAdmin {
+		t.Errorf("ListRoles returned %+v, %v", roles, err)
+	}
+
+	if valid, err := client.Roles.CheckAuth(ctx); !valid || err != nil {
+		t.Errorf("Expected a valid token, got %v, %v", valid, err)
+	}
+	validToken = false
+	if valid, err := client.Roles.CheckAuth(ctx); valid || err != nil {
+		t.Errorf("Expected an invalid token without error, got %v, %v", valid, err)
+	}
+}